@@ -1,78 +1,329 @@
 package app
 
 import (
+	"context"
+	"errors"
+	"fmt"
 	"log/slog"
+	"net/http"
 	"os"
+	"sync/atomic"
+	"time"
 
 	"github.com/gin-gonic/gin"
+	"github.com/ivanjabrony/personApi/cmd/config"
+	"github.com/ivanjabrony/personApi/cmd/initDB"
 	"github.com/ivanjabrony/personApi/internal/client"
+	"github.com/ivanjabrony/personApi/internal/client/client_batch"
+	"github.com/ivanjabrony/personApi/internal/client/client_cache"
 	"github.com/ivanjabrony/personApi/internal/client/client_impl"
+	"github.com/ivanjabrony/personApi/internal/client/client_offline"
+	"github.com/ivanjabrony/personApi/internal/client/client_registry"
+	"github.com/ivanjabrony/personApi/internal/client/client_transport"
 	"github.com/ivanjabrony/personApi/internal/controller"
+	"github.com/ivanjabrony/personApi/internal/metrics"
 	"github.com/ivanjabrony/personApi/internal/repository"
 	"github.com/ivanjabrony/personApi/internal/repository/pg"
+	"github.com/ivanjabrony/personApi/internal/repository/repository_metrics"
 	"github.com/ivanjabrony/personApi/internal/service"
 	"github.com/ivanjabrony/personApi/internal/service/service_impl"
+	"github.com/ivanjabrony/personApi/internal/service/service_tracing"
+	"github.com/ivanjabrony/personApi/internal/tracing"
+	"github.com/ivanjabrony/personApi/internal/worker"
 	"github.com/jmoiron/sqlx"
 )
 
 type App struct {
-	Router *gin.Engine
-	db     *sqlx.DB
+	Router     *gin.Engine
+	db         *sqlx.DB
+	worker     *worker.EnrichmentWorker
+	enrichment service.EnrichmentService
+	logger     *slog.Logger
+	ready      *atomic.Bool
+	shutdown   ShutdownConfig
+	// flushTraces exports the spans still buffered.
+	flushTraces func(context.Context) error
 }
 
-func New(db *sqlx.DB) *App {
-	repositories := initRepositories(db)
-	clients := initClients()
+type ShutdownConfig struct {
+	// Delay keeps serving after readiness is lost, so load balancers stop
+	// sending new requests before the listener closes.
+	Delay time.Duration
+	// GracePeriod bounds draining connections and stopping the worker.
+	GracePeriod time.Duration
+}
+
+func New(cfg *config.Config, db *sqlx.DB) (*App, error) {
 	logger := slog.New(slog.NewTextHandler(os.Stdout, &slog.HandlerOptions{Level: getLogLevel()}))
-	services := initServices(repositories, clients, logger)
+	flushTraces, err := tracing.Setup(context.Background(), tracing.Config{
+		Exporter:    cfg.Tracing.Exporter,
+		Endpoint:    cfg.Tracing.OTLPEndpoint,
+		SampleRatio: cfg.Tracing.SampleRatio,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	m := metrics.New()
+	m.RegisterDB(db.DB, cfg.Database.Name)
+
+	repositories := initRepositories(db, m)
+	clients, err := initClients(cfg, repositories, m, logger)
+	if err != nil {
+		return nil, err
+	}
+	ready := &atomic.Bool{}
+	services, err := initServices(cfg, repositories, clients, ready.Load, logger)
+	if err != nil {
+		return nil, err
+	}
 
 	router := controller.SetupRouter(
 		logger,
 		services.person,
+		services.enrichment,
+		services.diagnostics,
+		services.health,
+		m,
+		controller.Config{
+			PurgeRetention: cfg.Persons.PurgeRetention,
+			BatchMaxSize:   cfg.Persons.BatchMaxSize,
+		},
 	)
 
+	enrichmentWorker := worker.NewEnrichmentWorker(services.enrichment, logger, worker.Config{
+		Workers:      cfg.Enrichment.Jobs.Workers,
+		BatchSize:    cfg.Enrichment.Jobs.BatchSize,
+		PollInterval: cfg.Enrichment.Jobs.PollInterval,
+	})
+
 	return &App{
-		Router: router,
-		db:     db,
+		Router:     router,
+		db:         db,
+		worker:     enrichmentWorker,
+		enrichment: services.enrichment,
+		logger:     logger,
+		ready:      ready,
+		shutdown: ShutdownConfig{
+			Delay:       cfg.Server.ShutdownDelay,
+			GracePeriod: cfg.Server.ShutdownGracePeriod,
+		},
+		flushTraces: flushTraces,
+	}, nil
+}
+
+// Run serves addr and runs the enrichment worker until ctx is cancelled,
+// then shuts down gracefully and closes the database.
+func (a *App) Run(ctx context.Context, addr string) error {
+	server := &http.Server{
+		Addr:              addr,
+		Handler:           a.Router,
+		ReadHeaderTimeout: 10 * time.Second,
+	}
+
+	workerCtx, stopWorker := context.WithCancel(context.Background())
+	defer stopWorker()
+	workerDone := make(chan struct{})
+	go func() {
+		defer close(workerDone)
+		a.worker.Run(workerCtx)
+	}()
+
+	serveErr := make(chan error, 1)
+	go func() {
+		serveErr <- server.ListenAndServe()
+	}()
+
+	a.ready.Store(true)
+	a.logger.Info("Server started", slog.String("Address", addr))
+
+	var errs error
+	select {
+	case <-ctx.Done():
+	case err := <-serveErr:
+		errs = fmt.Errorf("server failed: %w", err)
+	}
+
+	a.ready.Store(false)
+	a.logger.Info("Shutting down", slog.Duration("GracePeriod", a.shutdown.GracePeriod))
+
+	if errs == nil && a.shutdown.Delay > 0 {
+		time.Sleep(a.shutdown.Delay)
+	}
+
+	shutdownCtx, cancel := context.WithTimeout(context.Background(), a.shutdown.GracePeriod)
+	defer cancel()
+
+	if err := server.Shutdown(shutdownCtx); err != nil {
+		errs = errors.Join(errs, fmt.Errorf("failed to drain connections: %w", err))
+	}
+
+	stopWorker()
+	select {
+	case <-workerDone:
+	case <-shutdownCtx.Done():
+		errs = errors.Join(errs, errors.New("enrichment worker did not stop in time"))
+	}
+
+	if err := a.Close(); err != nil {
+		errs = errors.Join(errs, err)
+	}
+
+	a.logger.Info("Shutdown complete")
+	return errs
+}
+
+// Close releases the database pool and flushes the remaining spans.
+func (a *App) Close() error {
+	var errs error
+	if err := a.db.Close(); err != nil {
+		errs = fmt.Errorf("failed to close database: %w", err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	if err := a.flushTraces(ctx); err != nil {
+		errs = errors.Join(errs, fmt.Errorf("failed to flush traces: %w", err))
 	}
+
+	return errs
 }
 
-func (a *App) Run(addr string) error {
-	return a.Router.Run(addr)
+// Backfill enriches persons with missing or outdated enrichment and returns
+// how many were queued.
+func (a *App) Backfill(ctx context.Context, config worker.BackfillConfig) (int, error) {
+	return worker.NewBackfiller(a.enrichment, a.logger, config).Run(ctx)
 }
 
 type repositories struct {
-	person repository.PersonRepository
+	person          repository.PersonRepository
+	enrichmentCache repository.EnrichmentCacheRepository
+	enrichmentJob   repository.EnrichmentJobRepository
+	health          repository.HealthRepository
 }
 
 type clients struct {
 	ageClient         client.AgeClient
 	genderClient      client.GenderClient
 	nationalityClient client.NationalityClient
+	transport         *client_transport.Transport
 }
 
 type services struct {
-	person service.PersonService
+	person      service.PersonService
+	enrichment  service.EnrichmentService
+	diagnostics service.DiagnosticsService
+	health      service.HealthService
 }
 
-func initRepositories(db *sqlx.DB) *repositories {
+func initRepositories(db *sqlx.DB, m *metrics.Metrics) *repositories {
 	return &repositories{
-		person: pg.NewPgPersonRepository(db),
+		person:          repository_metrics.NewInstrumentedPersonRepository(pg.NewPgPersonRepository(db), m),
+		enrichmentCache: repository_metrics.NewInstrumentedEnrichmentCacheRepository(pg.NewPgEnrichmentCacheRepository(db), m),
+		enrichmentJob:   repository_metrics.NewInstrumentedEnrichmentJobRepository(pg.NewPgEnrichmentJobRepository(db), m),
+		health:          pg.NewPgHealthRepository(db),
 	}
 }
 
-func initClients() *clients {
-	return &clients{
-		ageClient:         client_impl.NewAgifyClient(),
-		genderClient:      client_impl.NewGenderizeClient(),
-		nationalityClient: client_impl.NewNationalityClient(),
+func initClients(cfg *config.Config, r *repositories, m *metrics.Metrics, logger *slog.Logger) (*clients, error) {
+	dataset, err := client_offline.LoadDataset(cfg.Enrichment.Providers.OfflineDataset)
+	if err != nil {
+		return nil, err
+	}
+
+	transport := client_transport.NewTransport(client_transport.Config{
+		Timeout:             cfg.Enrichment.HTTP.Timeout,
+		AttemptTimeout:      cfg.Enrichment.HTTP.AttemptTimeout,
+		MaxRetries:          cfg.Enrichment.HTTP.MaxRetries,
+		RetryBackoff:        cfg.Enrichment.HTTP.RetryBackoff,
+		MaxRetryBackoff:     cfg.Enrichment.HTTP.MaxRetryBackoff,
+		BreakerThreshold:    cfg.Enrichment.HTTP.BreakerThreshold,
+		BreakerCooldown:     cfg.Enrichment.HTTP.BreakerCooldown,
+		MaxIdleConnsPerHost: cfg.Enrichment.HTTP.MaxIdleConnsPerHost,
+		RateLimit:           cfg.Enrichment.HTTP.RateLimit,
+		RateBurst:           cfg.Enrichment.HTTP.RateBurst,
+	}, m)
+	httpClient := client_transport.NewHTTPClient(transport)
+
+	agifyClient := client_impl.NewAgifyClient(httpClient, cfg.Enrichment.HTTP.APIKey)
+	genderizeClient := client_impl.NewGenderizeClient(httpClient, cfg.Enrichment.HTTP.APIKey)
+	nationalizeClient := client_impl.NewNationalityClient(httpClient, cfg.Enrichment.HTTP.APIKey)
+	for _, baseURL := range []string{agifyClient.BaseURL, genderizeClient.BaseURL, nationalizeClient.BaseURL} {
+		transport.Track(baseURL)
+	}
+
+	batchConfig := client_batch.Config{
+		Window:  cfg.Enrichment.HTTP.BatchWindow,
+		MaxSize: cfg.Enrichment.HTTP.BatchSize,
+	}
+
+	registry := client_registry.NewRegistry()
+	registry.RegisterAge("http", client_batch.NewBatchingAgeClient(agifyClient, batchConfig))
+	registry.RegisterGender("http", client_batch.NewBatchingGenderClient(genderizeClient, batchConfig))
+	registry.RegisterNationality("http", client_batch.NewBatchingNationalityClient(nationalizeClient, batchConfig))
+	registry.RegisterAge("offline", client_offline.NewOfflineAgeClient(dataset))
+	registry.RegisterGender("offline", client_offline.NewOfflineGenderClient(dataset))
+	registry.RegisterNationality("offline", client_offline.NewOfflineNationalityClient(dataset))
+
+	ageClient, err := registry.AgeClient(cfg.Enrichment.Providers.Age)
+	if err != nil {
+		return nil, err
+	}
+	genderClient, err := registry.GenderClient(cfg.Enrichment.Providers.Gender)
+	if err != nil {
+		return nil, err
 	}
+	nationalityClient, err := registry.NationalityClient(cfg.Enrichment.Providers.Nationality)
+	if err != nil {
+		return nil, err
+	}
+
+	cacheConfig := client_cache.Config{
+		TTL:          cfg.Enrichment.Cache.TTL,
+		MaxSize:      cfg.Enrichment.Cache.Size,
+		FetchTimeout: cfg.Enrichment.LookupTimeout,
+	}
+
+	var store repository.EnrichmentCacheRepository
+	if cfg.Enrichment.Cache.Persistent {
+		store = r.enrichmentCache
+	}
+
+	return &clients{
+		ageClient:         client_cache.NewCachedAgeClient(ageClient, cacheConfig, store, logger),
+		genderClient:      client_cache.NewCachedGenderClient(genderClient, cacheConfig, store, logger),
+		nationalityClient: client_cache.NewCachedNationalityClient(nationalityClient, cacheConfig, store, logger),
+		transport:         transport,
+	}, nil
 }
 
-func initServices(r *repositories, cl *clients, logger *slog.Logger) *services {
-	return &services{
-		person: service_impl.NewPersonService(r.person, cl.ageClient, cl.genderClient, cl.nationalityClient, logger),
+func initServices(cfg *config.Config, r *repositories, cl *clients, ready func() bool, logger *slog.Logger) (*services, error) {
+	schemaVersion, err := initDB.LatestVersion()
+	if err != nil {
+		return nil, err
+	}
+
+	enrichmentConfig := service_impl.EnrichmentServiceConfig{
+		EnrichmentConcurrency: cfg.Enrichment.Concurrency,
+		LookupTimeout:         cfg.Enrichment.LookupTimeout,
+		JobLease:              cfg.Enrichment.Jobs.Lease,
+		MaxAttempts:           cfg.Enrichment.Jobs.MaxAttempts,
+		RetryBackoff:          cfg.Enrichment.Jobs.RetryBackoff,
+		MaxRetryBackoff:       cfg.Enrichment.Jobs.MaxRetryBackoff,
 	}
+
+	healthConfig := service_impl.HealthServiceConfig{
+		SchemaVersion: schemaVersion,
+		CheckTimeout:  cfg.Health.CheckTimeout,
+		CacheTTL:      cfg.Health.CacheTTL,
+	}
+
+	return &services{
+		person:      service_tracing.NewTracedPersonService(service_impl.NewPersonService(r.person, logger)),
+		enrichment:  service_tracing.NewTracedEnrichmentService(service_impl.NewEnrichmentService(r.person, r.enrichmentJob, cl.ageClient, cl.genderClient, cl.nationalityClient, logger, enrichmentConfig)),
+		diagnostics: service_impl.NewDiagnosticsService(cl.transport),
+		health:      service_impl.NewHealthService(r.health, cl.transport, ready, logger, healthConfig),
+	}, nil
 }
 
 func getLogLevel() slog.Level {
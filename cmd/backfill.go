@@ -0,0 +1,36 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+
+	"github.com/ivanjabrony/personApi/cmd/app"
+	"github.com/ivanjabrony/personApi/cmd/config"
+	"github.com/ivanjabrony/personApi/internal/worker"
+)
+
+// runBackfill implements the backfill subcommand:
+//
+//	backfill [-batch 50] [-interval 5s] [-stale-after 2160h]
+func runBackfill(application *app.App, cfg *config.Config, args []string) (int, error) {
+	flags := flag.NewFlagSet("backfill", flag.ExitOnError)
+	batchSize := flags.Int("batch", 50, "persons queued and enriched per batch")
+	interval := flags.Duration("interval", 5*time.Second, "minimal pause between the starts of two batches")
+	staleAfter := flags.Duration("stale-after", cfg.Enrichment.StaleAfter, "age after which an enrichment is refreshed")
+	if err := flags.Parse(args); err != nil {
+		return 0, err
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	return application.Backfill(ctx, worker.BackfillConfig{
+		BatchSize:  *batchSize,
+		Interval:   *interval,
+		StaleAfter: *staleAfter,
+	})
+}
@@ -3,18 +3,76 @@ package config
 import (
 	"fmt"
 	"os"
+	"strconv"
+	"time"
 )
 
 type Config struct {
 	Database struct {
-		Host     string
-		Port     string
-		User     string
-		Password string
-		Name     string
+		Host           string
+		Port           string
+		User           string
+		Password       string
+		Name           string
+		MigrateOnStart bool
 	}
 	Server struct {
-		Port string
+		Port                string
+		ShutdownDelay       time.Duration
+		ShutdownGracePeriod time.Duration
+	}
+	Persons struct {
+		PurgeRetention time.Duration
+		BatchMaxSize   int
+	}
+	Health struct {
+		CheckTimeout time.Duration
+		CacheTTL     time.Duration
+	}
+	Tracing struct {
+		Exporter     string
+		OTLPEndpoint string
+		SampleRatio  float64
+	}
+	Enrichment struct {
+		Concurrency   int
+		LookupTimeout time.Duration
+		Cache         struct {
+			TTL        time.Duration
+			Size       int
+			Persistent bool
+		}
+		Jobs struct {
+			Workers         int
+			BatchSize       int
+			PollInterval    time.Duration
+			Lease           time.Duration
+			MaxAttempts     int
+			RetryBackoff    time.Duration
+			MaxRetryBackoff time.Duration
+		}
+		StaleAfter time.Duration
+		Providers  struct {
+			Age            string
+			Gender         string
+			Nationality    string
+			OfflineDataset string
+		}
+		HTTP struct {
+			Timeout             time.Duration
+			AttemptTimeout      time.Duration
+			MaxRetries          int
+			RetryBackoff        time.Duration
+			MaxRetryBackoff     time.Duration
+			BreakerThreshold    int
+			BreakerCooldown     time.Duration
+			MaxIdleConnsPerHost int
+			RateLimit           float64
+			RateBurst           int
+			APIKey              string
+			BatchWindow         time.Duration
+			BatchSize           int
+		}
 	}
 }
 
@@ -26,11 +84,63 @@ func New() *Config {
 	cfg.Database.User = os.Getenv("DATABASE_USER")
 	cfg.Database.Password = os.Getenv("DATABASE_PASSWORD")
 	cfg.Database.Name = os.Getenv("DATABASE_NAME")
+	cfg.Database.MigrateOnStart = getEnvBool("MIGRATE_ON_START", false)
 	cfg.Server.Port = ":" + os.Getenv("SERVER_PORT")
+	cfg.Server.ShutdownDelay = getEnvDuration("SHUTDOWN_DELAY", 0)
+	cfg.Server.ShutdownGracePeriod = getEnvDuration("SHUTDOWN_GRACE_PERIOD", 20*time.Second)
+	cfg.Persons.PurgeRetention = getEnvDuration("PURGE_RETENTION", 30*24*time.Hour)
+	cfg.Persons.BatchMaxSize = getEnvInt("BATCH_MAX_SIZE", 100)
+	cfg.Health.CheckTimeout = getEnvDuration("HEALTH_CHECK_TIMEOUT", 2*time.Second)
+	cfg.Health.CacheTTL = getEnvDuration("HEALTH_CACHE_TTL", 5*time.Second)
+	cfg.Tracing.Exporter = getEnvString("TRACING_EXPORTER", "none")
+	cfg.Tracing.OTLPEndpoint = os.Getenv("TRACING_OTLP_ENDPOINT")
+	cfg.Tracing.SampleRatio = getEnvFloat("TRACING_SAMPLE_RATIO", 1)
+	cfg.Enrichment.Concurrency = getEnvInt("ENRICHMENT_CONCURRENCY", 8)
+	cfg.Enrichment.LookupTimeout = getEnvDuration("ENRICHMENT_LOOKUP_TIMEOUT", 2*time.Second)
+	cfg.Enrichment.Cache.TTL = getEnvDuration("ENRICHMENT_CACHE_TTL", 24*time.Hour)
+	cfg.Enrichment.Cache.Size = getEnvInt("ENRICHMENT_CACHE_SIZE", 10000)
+	cfg.Enrichment.Cache.Persistent = getEnvBool("ENRICHMENT_CACHE_PERSISTENT", false)
+	cfg.Enrichment.Jobs.Workers = getEnvInt("ENRICHMENT_WORKERS", 2)
+	cfg.Enrichment.Jobs.BatchSize = getEnvInt("ENRICHMENT_JOB_BATCH_SIZE", 20)
+	cfg.Enrichment.Jobs.PollInterval = getEnvDuration("ENRICHMENT_POLL_INTERVAL", time.Second)
+	cfg.Enrichment.Jobs.Lease = getEnvDuration("ENRICHMENT_JOB_LEASE", time.Minute)
+	cfg.Enrichment.Jobs.MaxAttempts = getEnvInt("ENRICHMENT_MAX_ATTEMPTS", 5)
+	cfg.Enrichment.Jobs.RetryBackoff = getEnvDuration("ENRICHMENT_RETRY_BACKOFF", 5*time.Second)
+	cfg.Enrichment.Jobs.MaxRetryBackoff = getEnvDuration("ENRICHMENT_MAX_RETRY_BACKOFF", 10*time.Minute)
+	cfg.Enrichment.StaleAfter = getEnvDuration("ENRICHMENT_STALE_AFTER", 90*24*time.Hour)
+	cfg.Enrichment.Providers.Age = getEnvString("ENRICHMENT_AGE_PROVIDER", "http")
+	cfg.Enrichment.Providers.Gender = getEnvString("ENRICHMENT_GENDER_PROVIDER", "http")
+	cfg.Enrichment.Providers.Nationality = getEnvString("ENRICHMENT_NATIONALITY_PROVIDER", "http")
+	cfg.Enrichment.Providers.OfflineDataset = os.Getenv("ENRICHMENT_OFFLINE_DATASET")
+	cfg.Enrichment.HTTP.Timeout = getEnvDuration("ENRICHMENT_HTTP_TIMEOUT", 5*time.Second)
+	cfg.Enrichment.HTTP.AttemptTimeout = getEnvDuration("ENRICHMENT_HTTP_ATTEMPT_TIMEOUT", 2*time.Second)
+	cfg.Enrichment.HTTP.MaxRetries = getEnvInt("ENRICHMENT_HTTP_MAX_RETRIES", 2)
+	cfg.Enrichment.HTTP.RetryBackoff = getEnvDuration("ENRICHMENT_HTTP_RETRY_BACKOFF", 200*time.Millisecond)
+	cfg.Enrichment.HTTP.MaxRetryBackoff = getEnvDuration("ENRICHMENT_HTTP_MAX_RETRY_BACKOFF", 2*time.Second)
+	cfg.Enrichment.HTTP.BreakerThreshold = getEnvInt("ENRICHMENT_BREAKER_THRESHOLD", 5)
+	cfg.Enrichment.HTTP.BreakerCooldown = getEnvDuration("ENRICHMENT_BREAKER_COOLDOWN", 30*time.Second)
+	cfg.Enrichment.HTTP.MaxIdleConnsPerHost = getEnvInt("ENRICHMENT_HTTP_MAX_IDLE_CONNS", 16)
+	cfg.Enrichment.HTTP.RateLimit = getEnvFloat("ENRICHMENT_HTTP_RATE_LIMIT", 0)
+	cfg.Enrichment.HTTP.RateBurst = getEnvInt("ENRICHMENT_HTTP_RATE_BURST", 1)
+	cfg.Enrichment.HTTP.APIKey = os.Getenv("ENRICHMENT_API_KEY")
+	cfg.Enrichment.HTTP.BatchWindow = getEnvDuration("ENRICHMENT_BATCH_WINDOW", 20*time.Millisecond)
+	cfg.Enrichment.HTTP.BatchSize = getEnvInt("ENRICHMENT_BATCH_SIZE", 10)
 
 	return cfg
 }
 
+// Validate rejects settings the service can't run with.
+func (c *Config) Validate() error {
+	if c.Persons.PurgeRetention <= 0 {
+		return fmt.Errorf("PURGE_RETENTION must be positive, got %s", c.Persons.PurgeRetention)
+	}
+	if c.Persons.BatchMaxSize < 1 {
+		return fmt.Errorf("BATCH_MAX_SIZE must be at least 1, got %d", c.Persons.BatchMaxSize)
+	}
+
+	return nil
+}
+
 func (c *Config) GetDB() string {
 	return fmt.Sprintf(
 		"host=%s port=%s user=%s password=%s dbname=%s sslmode=disable",
@@ -41,3 +151,43 @@ func (c *Config) GetDB() string {
 		c.Database.Name,
 	)
 }
+
+func getEnvString(key string, defaultValue string) string {
+	value := os.Getenv(key)
+	if value == "" {
+		return defaultValue
+	}
+	return value
+}
+
+func getEnvInt(key string, defaultValue int) int {
+	value, err := strconv.Atoi(os.Getenv(key))
+	if err != nil {
+		return defaultValue
+	}
+	return value
+}
+
+func getEnvFloat(key string, defaultValue float64) float64 {
+	value, err := strconv.ParseFloat(os.Getenv(key), 64)
+	if err != nil {
+		return defaultValue
+	}
+	return value
+}
+
+func getEnvDuration(key string, defaultValue time.Duration) time.Duration {
+	value, err := time.ParseDuration(os.Getenv(key))
+	if err != nil {
+		return defaultValue
+	}
+	return value
+}
+
+func getEnvBool(key string, defaultValue bool) bool {
+	value, err := strconv.ParseBool(os.Getenv(key))
+	if err != nil {
+		return defaultValue
+	}
+	return value
+}
@@ -1,70 +1,39 @@
 package initDB
 
 import (
-	"errors"
+	"context"
+	"database/sql/driver"
 
-	"github.com/golang-migrate/migrate/v4"
-	"github.com/golang-migrate/migrate/v4/database/postgres"
+	"github.com/XSAM/otelsql"
 	"github.com/ivanjabrony/personApi/cmd/config"
+	"github.com/ivanjabrony/personApi/internal/tracing"
 	"github.com/jmoiron/sqlx"
+	semconv "go.opentelemetry.io/otel/semconv/v1.26.0"
 )
 
+// InitDatabase opens the pool with a span per query. Queries only use $n
+// placeholders, so the recorded statement never carries values.
 func InitDatabase(cfg *config.Config) (*sqlx.DB, error) {
-	db, err := sqlx.Open("postgres", cfg.GetDB())
+	db, err := otelsql.Open("postgres", cfg.GetDB(),
+		otelsql.WithAttributes(semconv.DBSystemPostgreSQL),
+		otelsql.WithSpanOptions(otelsql.SpanOptions{
+			DisableErrSkip:       true,
+			OmitConnResetSession: true,
+			OmitConnPrepare:      true,
+			OmitRows:             true,
+			SpanFilter: func(ctx context.Context, _ otelsql.Method, _ string, _ []driver.NamedValue) bool {
+				return tracing.InTrace(ctx)
+			},
+		}),
+	)
 	if err != nil {
 		return nil, err
 	}
 
-	if err := db.Ping(); err != nil {
+	sqlxDB := sqlx.NewDb(db, "postgres")
+	if err := sqlxDB.Ping(); err != nil {
 		return nil, err
 	}
 
-	return db, nil
-}
-
-func RunMigrations(db *sqlx.DB, dbName string, sourceMigration string) error {
-	driver, err := postgres.WithInstance(db.DB, &postgres.Config{})
-	if err != nil {
-		return err
-	}
-
-	m, err := migrate.NewWithDatabaseInstance(
-		sourceMigration,
-		dbName,
-		driver,
-	)
-	if err != nil {
-		return err
-	}
-	if err := m.Down(); err != nil && !errors.Is(err, migrate.ErrNoChange) {
-		return err
-	}
-
-	if err := m.Up(); err != nil && !errors.Is(err, migrate.ErrNoChange) {
-		return err
-	}
-
-	return nil
-}
-
-func DownMigrations(db *sqlx.DB, dbName string, sourceMigration string) error {
-	driver, err := postgres.WithInstance(db.DB, &postgres.Config{})
-	if err != nil {
-		return err
-	}
-
-	m, err := migrate.NewWithDatabaseInstance(
-		sourceMigration,
-		dbName,
-		driver,
-	)
-	if err != nil {
-		return err
-	}
-
-	if err := m.Down(); err != nil && !errors.Is(err, migrate.ErrNoChange) {
-		return err
-	}
-
-	return nil
+	return sqlxDB, nil
 }
@@ -0,0 +1,189 @@
+package initDB
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io/fs"
+	"log"
+	"os"
+
+	"github.com/golang-migrate/migrate/v4"
+	"github.com/golang-migrate/migrate/v4/database/postgres"
+	"github.com/golang-migrate/migrate/v4/source"
+	"github.com/golang-migrate/migrate/v4/source/iofs"
+	"github.com/ivanjabrony/personApi/migrations"
+	"github.com/jmoiron/sqlx"
+)
+
+// Migrator applies the migrations embedded into the binary. It holds a
+// connection of the pool until it is closed.
+type Migrator struct {
+	migrate *migrate.Migrate
+	source  source.Driver
+}
+
+type Migration struct {
+	Version uint
+	Name    string
+}
+
+type MigrationStatus struct {
+	// Version is 0 when no migration has been applied yet.
+	Version uint
+	Dirty   bool
+	Latest  uint
+	Pending []Migration
+}
+
+func NewMigrator(db *sqlx.DB, dbName string) (*Migrator, error) {
+	src, err := iofs.New(migrations.FS, ".")
+	if err != nil {
+		return nil, fmt.Errorf("failed to read embedded migrations: %w", err)
+	}
+
+	// A driver made from the pool would close the whole pool on Close,
+	// one made from a single connection only gives that connection back.
+	conn, err := db.Conn(context.Background())
+	if err != nil {
+		src.Close()
+		return nil, fmt.Errorf("failed to get a connection for migrations: %w", err)
+	}
+
+	driver, err := postgres.WithConnection(context.Background(), conn, &postgres.Config{DatabaseName: dbName})
+	if err != nil {
+		src.Close()
+		conn.Close()
+		return nil, fmt.Errorf("failed to create migration driver: %w", err)
+	}
+
+	m, err := migrate.NewWithInstance("iofs", src, dbName, driver)
+	if err != nil {
+		src.Close()
+		driver.Close()
+		return nil, fmt.Errorf("failed to create migrator: %w", err)
+	}
+	m.Log = migrateLogger{log.New(os.Stdout, "migrate: ", log.LstdFlags)}
+
+	return &Migrator{migrate: m, source: src}, nil
+}
+
+// RunMigrations applies pending migrations, it never rolls anything back.
+func RunMigrations(db *sqlx.DB, dbName string) (err error) {
+	m, err := NewMigrator(db, dbName)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		err = errors.Join(err, m.Close())
+	}()
+
+	if err := m.Up(); err != nil && !errors.Is(err, migrate.ErrNoChange) {
+		return err
+	}
+
+	return nil
+}
+
+// Close releases the embedded source and the connection.
+func (m *Migrator) Close() error {
+	sourceErr, databaseErr := m.migrate.Close()
+	if sourceErr != nil {
+		sourceErr = fmt.Errorf("failed to close migration source: %w", sourceErr)
+	}
+	if databaseErr != nil {
+		databaseErr = fmt.Errorf("failed to close migration connection: %w", databaseErr)
+	}
+
+	return errors.Join(sourceErr, databaseErr)
+}
+
+// Up applies every pending migration.
+func (m *Migrator) Up() error {
+	return m.migrate.Up()
+}
+
+// Down rolls the last steps migrations back.
+func (m *Migrator) Down(steps int) error {
+	return m.migrate.Steps(-steps)
+}
+
+// Goto migrates up or down to version.
+func (m *Migrator) Goto(version uint) error {
+	return m.migrate.Migrate(version)
+}
+
+// Force records version as applied and clean without running anything.
+func (m *Migrator) Force(version int) error {
+	return m.migrate.Force(version)
+}
+
+// Status compares the applied version with the embedded migrations.
+func (m *Migrator) Status() (*MigrationStatus, error) {
+	status := &MigrationStatus{}
+
+	version, dirty, err := m.migrate.Version()
+	if err != nil && !errors.Is(err, migrate.ErrNilVersion) {
+		return nil, fmt.Errorf("failed to read schema version: %w", err)
+	}
+	status.Version, status.Dirty = version, dirty
+
+	all, err := listMigrations(m.source)
+	if err != nil {
+		return nil, err
+	}
+	for _, migration := range all {
+		status.Latest = migration.Version
+		if migration.Version > status.Version {
+			status.Pending = append(status.Pending, migration)
+		}
+	}
+
+	return status, nil
+}
+
+// LatestVersion is the version of the newest embedded migration, the one
+// the schema is expected to be at.
+func LatestVersion() (uint, error) {
+	src, err := iofs.New(migrations.FS, ".")
+	if err != nil {
+		return 0, fmt.Errorf("failed to read embedded migrations: %w", err)
+	}
+	defer src.Close()
+
+	all, err := listMigrations(src)
+	if err != nil || len(all) == 0 {
+		return 0, err
+	}
+
+	return all[len(all)-1].Version, nil
+}
+
+func listMigrations(src source.Driver) ([]Migration, error) {
+	var all []Migration
+
+	version, err := src.First()
+	for err == nil {
+		migration := Migration{Version: version}
+		if r, name, readErr := src.ReadUp(version); readErr == nil {
+			r.Close()
+			migration.Name = name
+		}
+		all = append(all, migration)
+
+		version, err = src.Next(version)
+	}
+	if !errors.Is(err, fs.ErrNotExist) {
+		return nil, fmt.Errorf("failed to list embedded migrations: %w", err)
+	}
+
+	return all, nil
+}
+
+type migrateLogger struct {
+	*log.Logger
+}
+
+func (l migrateLogger) Verbose() bool {
+	return false
+}
@@ -5,9 +5,12 @@ package main
 // @description     Person managing API
 
 import (
+	"context"
 	"log"
+	"os"
+	"os/signal"
+	"syscall"
 
-	_ "github.com/golang-migrate/migrate/v4/source/file"
 	"github.com/ivanjabrony/personApi/cmd/app"
 	"github.com/ivanjabrony/personApi/cmd/config"
 	"github.com/ivanjabrony/personApi/cmd/initDB"
@@ -16,22 +19,51 @@ import (
 
 func main() {
 	cfg := config.New()
+	if err := cfg.Validate(); err != nil {
+		log.Fatalf("Invalid configuration: %v", err)
+	}
 
 	db, err := initDB.InitDatabase(cfg)
 	if err != nil {
 		log.Fatalf("Failed to initialize database: %v", err)
 	}
 
-	if err := initDB.RunMigrations(db, cfg.Database.Name, "file://migrations"); err != nil {
-		log.Fatalf("Failed to run migrations: %v", err)
+	if len(os.Args) > 1 && os.Args[1] == "migrate" {
+		err := runMigrate(db, cfg, os.Args[2:])
+		db.Close()
+		if err != nil {
+			log.Fatalf("Migrate failed: %v", err)
+		}
+		return
+	}
+
+	if cfg.Database.MigrateOnStart {
+		if err := initDB.RunMigrations(db, cfg.Database.Name); err != nil {
+			log.Fatalf("Failed to run migrations: %v", err)
+		}
 	}
 
-	application := app.New(db)
-	if err := application.Run(cfg.Server.Port); err != nil {
-		log.Fatalf("Failed to start server: %v", err)
+	application, err := app.New(cfg, db)
+	if err != nil {
+		log.Fatalf("Failed to initialize application: %v", err)
 	}
 
-	if err := initDB.DownMigrations(db, cfg.Database.Name, "file://migrations"); err != nil {
-		log.Fatalf("Failed to run migrations: %v", err)
+	if len(os.Args) > 1 && os.Args[1] == "backfill" {
+		queued, err := runBackfill(application, cfg, os.Args[2:])
+		if closeErr := application.Close(); closeErr != nil {
+			log.Printf("Failed to close application: %v", closeErr)
+		}
+		if err != nil {
+			log.Fatalf("Backfill stopped after queueing %d persons: %v", queued, err)
+		}
+		log.Printf("Backfill queued %d persons", queued)
+		return
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	if err := application.Run(ctx, cfg.Server.Port); err != nil {
+		log.Fatalf("Server stopped with error: %v", err)
 	}
 }
@@ -0,0 +1,118 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+
+	"github.com/golang-migrate/migrate/v4"
+	"github.com/ivanjabrony/personApi/cmd/config"
+	"github.com/ivanjabrony/personApi/cmd/initDB"
+	"github.com/jmoiron/sqlx"
+)
+
+const migrateUsage = "usage: migrate up | down N | goto V | version | force V | status"
+
+// runMigrate implements the migrate subcommand:
+//
+//	migrate up         apply every pending migration
+//	migrate down N     roll back the last N migrations
+//	migrate goto V     migrate up or down to version V
+//	migrate version    print the applied version
+//	migrate force V    mark version V as applied and clean, after a failed migration was fixed by hand
+//	migrate status     print the applied version and the pending migrations
+func runMigrate(db *sqlx.DB, cfg *config.Config, args []string) (err error) {
+	if len(args) == 0 {
+		return errors.New(migrateUsage)
+	}
+
+	m, err := initDB.NewMigrator(db, cfg.Database.Name)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		err = errors.Join(err, m.Close())
+	}()
+
+	switch command := args[0]; command {
+	case "up":
+		err = m.Up()
+	case "down":
+		var steps int
+		if steps, err = migrateArgument(args); err == nil {
+			if steps < 1 {
+				return errors.New("down needs a positive amount of migrations to roll back")
+			}
+			err = m.Down(steps)
+		}
+	case "goto":
+		var version int
+		if version, err = migrateArgument(args); err == nil {
+			if version < 1 {
+				return errors.New("goto needs a positive version, roll everything back with down N")
+			}
+			err = m.Goto(uint(version))
+		}
+	case "force":
+		var version int
+		if version, err = migrateArgument(args); err == nil {
+			err = m.Force(version)
+		}
+	case "version":
+		return printVersion(m)
+	case "status":
+		return printStatus(m)
+	default:
+		return fmt.Errorf("unknown migrate command %q, %s", command, migrateUsage)
+	}
+
+	if errors.Is(err, migrate.ErrNoChange) {
+		fmt.Println("no change")
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+
+	return printVersion(m)
+}
+
+func migrateArgument(args []string) (int, error) {
+	if len(args) != 2 {
+		return 0, fmt.Errorf("%s needs exactly one number, %s", args[0], migrateUsage)
+	}
+
+	value, err := strconv.Atoi(args[1])
+	if err != nil {
+		return 0, fmt.Errorf("%s needs a number, got %q", args[0], args[1])
+	}
+
+	return value, nil
+}
+
+func printVersion(m *initDB.Migrator) error {
+	status, err := m.Status()
+	if err != nil {
+		return err
+	}
+
+	fmt.Printf("version %d, dirty %t\n", status.Version, status.Dirty)
+	return nil
+}
+
+func printStatus(m *initDB.Migrator) error {
+	status, err := m.Status()
+	if err != nil {
+		return err
+	}
+
+	fmt.Printf("version %d, dirty %t, latest %d\n", status.Version, status.Dirty, status.Latest)
+	if len(status.Pending) == 0 {
+		fmt.Println("no pending migrations")
+	}
+	for _, migration := range status.Pending {
+		fmt.Printf("pending %d %s\n", migration.Version, migration.Name)
+	}
+
+	return nil
+}
@@ -15,6 +15,82 @@ const docTemplate = `{
     "host": "{{.Host}}",
     "basePath": "{{.BasePath}}",
     "paths": {
+        "/admin/enrichment/upstreams": {
+            "get": {
+                "description": "Reports circuit breaker, rate limit and remaining quota of every enrichment upstream",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "admin"
+                ],
+                "summary": "Enrichment upstreams state",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/dto.UpstreamStatusDto"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/admin/persons/purge": {
+            "post": {
+                "description": "Permanently removes persons soft deleted longer than the retention window ago",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "admin"
+                ],
+                "summary": "Purge deleted persons",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "example": "\"720h\"",
+                        "description": "Retention window as Go duration, at least and by default the configured one",
+                        "name": "older_than",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Who performs the change, recorded in the audit trail",
+                        "name": "X-Actor",
+                        "in": "header"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/dto.PurgeResultDto"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/dto.BadResponseDto"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/dto.BadResponseDto"
+                        }
+                    },
+                    "503": {
+                        "description": "Service Unavailable",
+                        "schema": {
+                            "$ref": "#/definitions/dto.BadResponseDto"
+                        }
+                    }
+                }
+            }
+        },
         "/persons": {
             "get": {
                 "description": "returning persons with pagination",
@@ -44,6 +120,12 @@ const docTemplate = `{
                         "description": "Amount of items on the page",
                         "name": "page_size",
                         "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Opaque cursor taken from next_cursor of the previous page",
+                        "name": "cursor",
+                        "in": "query"
                     }
                 ],
                 "responses": {
@@ -58,11 +140,23 @@ const docTemplate = `{
                         "schema": {
                             "$ref": "#/definitions/dto.BadResponseDto"
                         }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/dto.BadResponseDto"
+                        }
+                    },
+                    "503": {
+                        "description": "Service Unavailable",
+                        "schema": {
+                            "$ref": "#/definitions/dto.BadResponseDto"
+                        }
                     }
                 }
             },
-            "put": {
-                "description": "Updates existing user",
+            "post": {
+                "description": "Creates new person, age, gender and nationality are looked up in the background",
                 "consumes": [
                     "application/json"
                 ],
@@ -72,32 +166,64 @@ const docTemplate = `{
                 "tags": [
                     "person"
                 ],
-                "summary": "Update user",
+                "summary": "Create person",
                 "parameters": [
                     {
-                        "description": "Updated data",
+                        "description": "Person data",
                         "name": "request",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/dto.UpdatePersonDto"
+                            "$ref": "#/definitions/dto.NewPersonDto"
                         }
+                    },
+                    {
+                        "type": "string",
+                        "description": "Who performs the change, recorded in the audit trail",
+                        "name": "X-Actor",
+                        "in": "header"
                     }
                 ],
                 "responses": {
                     "204": {
-                        "description": "Update success"
+                        "description": "Creating Success"
                     },
                     "400": {
                         "description": "Bad Request",
                         "schema": {
                             "$ref": "#/definitions/dto.BadResponseDto"
                         }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/dto.BadResponseDto"
+                        }
+                    },
+                    "422": {
+                        "description": "Unprocessable Entity",
+                        "schema": {
+                            "$ref": "#/definitions/dto.BadResponseDto"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/dto.BadResponseDto"
+                        }
+                    },
+                    "503": {
+                        "description": "Service Unavailable",
+                        "schema": {
+                            "$ref": "#/definitions/dto.BadResponseDto"
+                        }
                     }
                 }
-            },
+            }
+        },
+        "/persons/batch": {
             "post": {
-                "description": "Creates new person",
+                "description": "Creates up to the configured amount of persons at once, queueing their enrichment. Invalid items are reported and skipped",
                 "consumes": [
                     "application/json"
                 ],
@@ -107,27 +233,63 @@ const docTemplate = `{
                 "tags": [
                     "person"
                 ],
-                "summary": "Create person",
+                "summary": "Create persons in batch",
                 "parameters": [
                     {
-                        "description": "Person data",
+                        "description": "Persons data",
                         "name": "request",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/dto.NewPersonDto"
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/dto.NewPersonDto"
+                            }
                         }
+                    },
+                    {
+                        "type": "string",
+                        "description": "Who performs the change, recorded in the audit trail",
+                        "name": "X-Actor",
+                        "in": "header"
                     }
                 ],
                 "responses": {
-                    "204": {
-                        "description": "Creating Success"
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/dto.BatchCreateResultDto"
+                        }
                     },
                     "400": {
                         "description": "Bad Request",
                         "schema": {
                             "$ref": "#/definitions/dto.BadResponseDto"
                         }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/dto.BadResponseDto"
+                        }
+                    },
+                    "413": {
+                        "description": "Request Entity Too Large",
+                        "schema": {
+                            "$ref": "#/definitions/dto.BadResponseDto"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/dto.BadResponseDto"
+                        }
+                    },
+                    "503": {
+                        "description": "Service Unavailable",
+                        "schema": {
+                            "$ref": "#/definitions/dto.BadResponseDto"
+                        }
                     }
                 }
             }
@@ -216,6 +378,19 @@ const docTemplate = `{
                         "name": "age_max",
                         "in": "query"
                     },
+                    {
+                        "type": "boolean",
+                        "description": "Include soft deleted persons",
+                        "name": "include_deleted",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "example": "\"-age,surname\"",
+                        "description": "Comma separated columns to sort by, prefixed with - for descending order",
+                        "name": "sort",
+                        "in": "query"
+                    },
                     {
                         "type": "integer",
                         "default": 1,
@@ -231,6 +406,12 @@ const docTemplate = `{
                         "description": "Amount of items on the page",
                         "name": "page_size",
                         "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Opaque cursor taken from next_cursor of the previous page",
+                        "name": "cursor",
+                        "in": "query"
                     }
                 ],
                 "responses": {
@@ -245,6 +426,24 @@ const docTemplate = `{
                         "schema": {
                             "$ref": "#/definitions/dto.BadResponseDto"
                         }
+                    },
+                    "422": {
+                        "description": "Unprocessable Entity",
+                        "schema": {
+                            "$ref": "#/definitions/dto.BadResponseDto"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/dto.BadResponseDto"
+                        }
+                    },
+                    "503": {
+                        "description": "Service Unavailable",
+                        "schema": {
+                            "$ref": "#/definitions/dto.BadResponseDto"
+                        }
                     }
                 }
             }
@@ -269,6 +468,103 @@ const docTemplate = `{
                         "name": "id",
                         "in": "path",
                         "required": true
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "Return the person even if it is soft deleted",
+                        "name": "include_deleted",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "ETag of cached representation",
+                        "name": "If-None-Match",
+                        "in": "header"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/dto.PersonDto"
+                        },
+                        "headers": {
+                            "ETag": {
+                                "type": "string",
+                                "description": "Version of the person"
+                            }
+                        }
+                    },
+                    "304": {
+                        "description": "Not modified"
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/dto.BadResponseDto"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/dto.BadResponseDto"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/dto.BadResponseDto"
+                        }
+                    },
+                    "503": {
+                        "description": "Service Unavailable",
+                        "schema": {
+                            "$ref": "#/definitions/dto.BadResponseDto"
+                        }
+                    }
+                }
+            },
+            "put": {
+                "description": "Replaces all editable fields of existing person, omitted optional fields are cleared",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "person"
+                ],
+                "summary": "Replace person",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Person ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "ETag of the person being replaced or *",
+                        "name": "If-Match",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "description": "Replacement data",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/dto.UpdatePersonDto"
+                        }
+                    },
+                    {
+                        "type": "string",
+                        "description": "Who performs the change, recorded in the audit trail",
+                        "name": "X-Actor",
+                        "in": "header"
                     }
                 ],
                 "responses": {
@@ -276,6 +572,12 @@ const docTemplate = `{
                         "description": "OK",
                         "schema": {
                             "$ref": "#/definitions/dto.PersonDto"
+                        },
+                        "headers": {
+                            "ETag": {
+                                "type": "string",
+                                "description": "New version of the person"
+                            }
                         }
                     },
                     "400": {
@@ -283,11 +585,53 @@ const docTemplate = `{
                         "schema": {
                             "$ref": "#/definitions/dto.BadResponseDto"
                         }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/dto.BadResponseDto"
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/dto.BadResponseDto"
+                        }
+                    },
+                    "412": {
+                        "description": "Precondition Failed",
+                        "schema": {
+                            "$ref": "#/definitions/dto.BadResponseDto"
+                        }
+                    },
+                    "422": {
+                        "description": "Unprocessable Entity",
+                        "schema": {
+                            "$ref": "#/definitions/dto.BadResponseDto"
+                        }
+                    },
+                    "428": {
+                        "description": "Precondition Required",
+                        "schema": {
+                            "$ref": "#/definitions/dto.BadResponseDto"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/dto.BadResponseDto"
+                        }
+                    },
+                    "503": {
+                        "description": "Service Unavailable",
+                        "schema": {
+                            "$ref": "#/definitions/dto.BadResponseDto"
+                        }
                     }
                 }
             },
             "delete": {
-                "description": "Deletes person by ID",
+                "description": "Soft deletes person by ID, it can be restored until purged",
                 "consumes": [
                     "application/json"
                 ],
@@ -305,6 +649,19 @@ const docTemplate = `{
                         "name": "id",
                         "in": "path",
                         "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "ETag of the person being deleted or *",
+                        "name": "If-Match",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Who performs the change, recorded in the audit trail",
+                        "name": "X-Actor",
+                        "in": "header"
                     }
                 ],
                 "responses": {
@@ -316,21 +673,536 @@ const docTemplate = `{
                         "schema": {
                             "$ref": "#/definitions/dto.BadResponseDto"
                         }
-                    }
-                }
-            }
-        }
-    },
-    "definitions": {
-        "dto.BadResponseDto": {
-            "type": "object",
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/dto.BadResponseDto"
+                        }
+                    },
+                    "412": {
+                        "description": "Precondition Failed",
+                        "schema": {
+                            "$ref": "#/definitions/dto.BadResponseDto"
+                        }
+                    },
+                    "428": {
+                        "description": "Precondition Required",
+                        "schema": {
+                            "$ref": "#/definitions/dto.BadResponseDto"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/dto.BadResponseDto"
+                        }
+                    },
+                    "503": {
+                        "description": "Service Unavailable",
+                        "schema": {
+                            "$ref": "#/definitions/dto.BadResponseDto"
+                        }
+                    }
+                }
+            },
+            "patch": {
+                "description": "Applies JSON Merge Patch (RFC 7396): absent fields are untouched, null clears patronymic, age, gender or nationality",
+                "consumes": [
+                    "application/json",
+                    "application/merge-patch+json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "person"
+                ],
+                "summary": "Partially update person",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Person ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "ETag of the person being patched or *",
+                        "name": "If-Match",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "description": "Merge patch",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/dto.PatchPersonDto"
+                        }
+                    },
+                    {
+                        "type": "string",
+                        "description": "Who performs the change, recorded in the audit trail",
+                        "name": "X-Actor",
+                        "in": "header"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/dto.PersonDto"
+                        },
+                        "headers": {
+                            "ETag": {
+                                "type": "string",
+                                "description": "New version of the person"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/dto.BadResponseDto"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/dto.BadResponseDto"
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/dto.BadResponseDto"
+                        }
+                    },
+                    "412": {
+                        "description": "Precondition Failed",
+                        "schema": {
+                            "$ref": "#/definitions/dto.BadResponseDto"
+                        }
+                    },
+                    "422": {
+                        "description": "Unprocessable Entity",
+                        "schema": {
+                            "$ref": "#/definitions/dto.BadResponseDto"
+                        }
+                    },
+                    "428": {
+                        "description": "Precondition Required",
+                        "schema": {
+                            "$ref": "#/definitions/dto.BadResponseDto"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/dto.BadResponseDto"
+                        }
+                    },
+                    "503": {
+                        "description": "Service Unavailable",
+                        "schema": {
+                            "$ref": "#/definitions/dto.BadResponseDto"
+                        }
+                    }
+                }
+            }
+        },
+        "/persons/{id}/enrich": {
+            "post": {
+                "description": "Queues a fresh lookup of age, gender and nationality that replaces the current values once it succeeds",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "person"
+                ],
+                "summary": "Enrich person again",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Person ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "202": {
+                        "description": "Accepted",
+                        "schema": {
+                            "$ref": "#/definitions/dto.EnrichmentStatusDto"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/dto.BadResponseDto"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/dto.BadResponseDto"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/dto.BadResponseDto"
+                        }
+                    },
+                    "503": {
+                        "description": "Service Unavailable",
+                        "schema": {
+                            "$ref": "#/definitions/dto.BadResponseDto"
+                        }
+                    }
+                }
+            }
+        },
+        "/persons/{id}/enrichment": {
+            "get": {
+                "description": "returning state of the background lookup of age, gender and nationality",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "person"
+                ],
+                "summary": "Get person enrichment status",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Person ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/dto.EnrichmentStatusDto"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/dto.BadResponseDto"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/dto.BadResponseDto"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/dto.BadResponseDto"
+                        }
+                    },
+                    "503": {
+                        "description": "Service Unavailable",
+                        "schema": {
+                            "$ref": "#/definitions/dto.BadResponseDto"
+                        }
+                    }
+                }
+            }
+        },
+        "/persons/{id}/history": {
+            "get": {
+                "description": "returning audit trail of person mutations, newest first",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "person"
+                ],
+                "summary": "Get person change history",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Person ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "default": 1,
+                        "description": "Page number (starting from 1)",
+                        "name": "page",
+                        "in": "query"
+                    },
+                    {
+                        "maximum": 100,
+                        "minimum": 1,
+                        "type": "integer",
+                        "default": 10,
+                        "description": "Amount of items on the page",
+                        "name": "page_size",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/dto.PaginatedPersonAuditDto"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/dto.BadResponseDto"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/dto.BadResponseDto"
+                        }
+                    },
+                    "503": {
+                        "description": "Service Unavailable",
+                        "schema": {
+                            "$ref": "#/definitions/dto.BadResponseDto"
+                        }
+                    }
+                }
+            }
+        },
+        "/persons/{id}/restore": {
+            "post": {
+                "description": "Restores soft deleted person by ID",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "person"
+                ],
+                "summary": "Restore person",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Person ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Who performs the change, recorded in the audit trail",
+                        "name": "X-Actor",
+                        "in": "header"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/dto.PersonDto"
+                        },
+                        "headers": {
+                            "ETag": {
+                                "type": "string",
+                                "description": "New version of the person"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/dto.BadResponseDto"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/dto.BadResponseDto"
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/dto.BadResponseDto"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/dto.BadResponseDto"
+                        }
+                    },
+                    "503": {
+                        "description": "Service Unavailable",
+                        "schema": {
+                            "$ref": "#/definitions/dto.BadResponseDto"
+                        }
+                    }
+                }
+            }
+        }
+    },
+    "definitions": {
+        "dto.BadResponseDto": {
+            "type": "object",
             "properties": {
+                "code": {
+                    "type": "string",
+                    "example": "internal_error"
+                },
+                "details": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/dto.FieldErrorDto"
+                    }
+                },
                 "error": {
                     "type": "string",
                     "example": "Server error"
                 }
             }
         },
+        "dto.BatchCreateResultDto": {
+            "type": "object",
+            "properties": {
+                "created": {
+                    "type": "integer",
+                    "example": 2
+                },
+                "failed": {
+                    "type": "integer",
+                    "example": 1
+                },
+                "results": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/dto.BatchItemResultDto"
+                    }
+                }
+            }
+        },
+        "dto.BatchItemResultDto": {
+            "type": "object",
+            "properties": {
+                "error": {
+                    "$ref": "#/definitions/dto.BadResponseDto"
+                },
+                "id": {
+                    "type": "integer",
+                    "example": 1
+                },
+                "index": {
+                    "type": "integer",
+                    "example": 0
+                }
+            }
+        },
+        "dto.EnrichmentCandidateDto": {
+            "type": "object",
+            "properties": {
+                "country_id": {
+                    "type": "string",
+                    "example": "RU"
+                },
+                "probability": {
+                    "type": "number",
+                    "example": 0.42
+                },
+                "sample_count": {
+                    "type": "integer",
+                    "example": 1532
+                },
+                "value": {
+                    "type": "string",
+                    "example": "RU"
+                }
+            }
+        },
+        "dto.EnrichmentDto": {
+            "type": "object",
+            "properties": {
+                "age": {
+                    "$ref": "#/definitions/dto.EnrichmentCandidateDto"
+                },
+                "gender": {
+                    "$ref": "#/definitions/dto.EnrichmentCandidateDto"
+                },
+                "nationality": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/dto.EnrichmentCandidateDto"
+                    }
+                }
+            }
+        },
+        "dto.EnrichmentStatusDto": {
+            "type": "object",
+            "properties": {
+                "attempts": {
+                    "type": "integer",
+                    "example": 2
+                },
+                "last_error": {
+                    "type": "string",
+                    "example": "age lookup: upstream unavailable"
+                },
+                "max_attempts": {
+                    "type": "integer",
+                    "example": 5
+                },
+                "next_attempt_at": {
+                    "type": "string",
+                    "example": "2025-01-02T15:04:05Z"
+                },
+                "person_id": {
+                    "type": "integer",
+                    "example": 1
+                },
+                "status": {
+                    "type": "string",
+                    "enum": [
+                        "pending",
+                        "complete",
+                        "failed"
+                    ],
+                    "example": "pending"
+                },
+                "updated_at": {
+                    "type": "string",
+                    "example": "2025-01-02T15:04:05Z"
+                }
+            }
+        },
+        "dto.FieldErrorDto": {
+            "type": "object",
+            "properties": {
+                "field": {
+                    "type": "string",
+                    "example": "surname"
+                },
+                "param": {
+                    "type": "string",
+                    "example": ""
+                },
+                "rule": {
+                    "type": "string",
+                    "example": "personname"
+                }
+            }
+        },
         "dto.NewPersonDto": {
             "type": "object",
             "required": [
@@ -338,20 +1210,52 @@ const docTemplate = `{
                 "surname"
             ],
             "properties": {
+                "country_hint": {
+                    "description": "CountryHint localizes the age and gender lookups.",
+                    "type": "string",
+                    "example": "RU"
+                },
                 "name": {
                     "type": "string",
+                    "maxLength": 100,
                     "example": "Ivan"
                 },
                 "patronymic": {
                     "type": "string",
+                    "maxLength": 100,
+                    "minLength": 1,
                     "example": "Vladimirovich"
                 },
                 "surname": {
                     "type": "string",
+                    "maxLength": 100,
                     "example": "Zabrodin"
                 }
             }
         },
+        "dto.PaginatedPersonAuditDto": {
+            "type": "object",
+            "properties": {
+                "data": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/dto.PersonAuditDto"
+                    }
+                },
+                "page": {
+                    "type": "integer"
+                },
+                "page_size": {
+                    "type": "integer"
+                },
+                "total": {
+                    "type": "integer"
+                },
+                "total_pages": {
+                    "type": "integer"
+                }
+            }
+        },
         "dto.PaginatedPersonsDto": {
             "type": "object",
             "properties": {
@@ -361,6 +1265,10 @@ const docTemplate = `{
                         "$ref": "#/definitions/dto.PersonDto"
                     }
                 },
+                "next_cursor": {
+                    "type": "string",
+                    "example": "eyJpZCI6MTB9"
+                },
                 "page": {
                     "type": "integer"
                 },
@@ -375,6 +1283,82 @@ const docTemplate = `{
                 }
             }
         },
+        "dto.PatchPersonDto": {
+            "type": "object",
+            "properties": {
+                "age": {
+                    "type": "integer",
+                    "maximum": 150,
+                    "minimum": 0,
+                    "example": 21
+                },
+                "gender": {
+                    "type": "string",
+                    "enum": [
+                        "male",
+                        "female"
+                    ],
+                    "example": "male"
+                },
+                "name": {
+                    "type": "string",
+                    "maxLength": 100,
+                    "minLength": 1,
+                    "example": "Ivan"
+                },
+                "nationality": {
+                    "type": "string",
+                    "example": "RU"
+                },
+                "patronymic": {
+                    "type": "string",
+                    "maxLength": 100,
+                    "minLength": 1,
+                    "example": "Vladimirovich"
+                },
+                "surname": {
+                    "type": "string",
+                    "maxLength": 100,
+                    "minLength": 1,
+                    "example": "Zabrodin"
+                }
+            }
+        },
+        "dto.PersonAuditDto": {
+            "type": "object",
+            "properties": {
+                "action": {
+                    "type": "string",
+                    "example": "update"
+                },
+                "actor": {
+                    "type": "string",
+                    "example": "operator@example.com"
+                },
+                "after": {
+                    "type": "object"
+                },
+                "before": {
+                    "type": "object"
+                },
+                "created_at": {
+                    "type": "string",
+                    "example": "2025-01-02T15:04:05Z"
+                },
+                "id": {
+                    "type": "integer",
+                    "example": 1
+                },
+                "person_id": {
+                    "type": "integer",
+                    "example": 1
+                },
+                "request_id": {
+                    "type": "string",
+                    "example": "6f1c0c1e2a9b4d7f"
+                }
+            }
+        },
         "dto.PersonDto": {
             "type": "object",
             "properties": {
@@ -382,6 +1366,30 @@ const docTemplate = `{
                     "type": "integer",
                     "example": 21
                 },
+                "country_hint": {
+                    "type": "string",
+                    "example": "RU"
+                },
+                "deleted_at": {
+                    "type": "string",
+                    "example": "2025-01-02T15:04:05Z"
+                },
+                "enriched_at": {
+                    "type": "string",
+                    "example": "2025-01-02T15:04:05Z"
+                },
+                "enrichment": {
+                    "$ref": "#/definitions/dto.EnrichmentDto"
+                },
+                "enrichment_status": {
+                    "type": "string",
+                    "enum": [
+                        "pending",
+                        "complete",
+                        "failed"
+                    ],
+                    "example": "complete"
+                },
                 "gender": {
                     "type": "string",
                     "example": "male"
@@ -405,32 +1413,106 @@ const docTemplate = `{
                 "surname": {
                     "type": "string",
                     "example": "Zabrodin"
+                },
+                "version": {
+                    "type": "integer",
+                    "example": 1
+                }
+            }
+        },
+        "dto.PurgeResultDto": {
+            "type": "object",
+            "properties": {
+                "deleted_before": {
+                    "type": "string",
+                    "example": "2025-01-02T15:04:05Z"
+                },
+                "purged": {
+                    "type": "integer",
+                    "example": 42
                 }
             }
         },
         "dto.UpdatePersonDto": {
             "type": "object",
             "required": [
-                "id"
+                "name",
+                "surname"
             ],
             "properties": {
-                "id": {
+                "age": {
                     "type": "integer",
-                    "example": 1
+                    "maximum": 150,
+                    "minimum": 0,
+                    "example": 21
+                },
+                "gender": {
+                    "type": "string",
+                    "enum": [
+                        "male",
+                        "female"
+                    ],
+                    "example": "male"
                 },
                 "name": {
                     "type": "string",
+                    "maxLength": 100,
                     "example": "Ivan"
                 },
+                "nationality": {
+                    "type": "string",
+                    "example": "RU"
+                },
                 "patronymic": {
                     "type": "string",
+                    "maxLength": 100,
+                    "minLength": 1,
                     "example": "Vladimirovich"
                 },
                 "surname": {
                     "type": "string",
+                    "maxLength": 100,
                     "example": "Zabrodin"
                 }
             }
+        },
+        "dto.UpstreamStatusDto": {
+            "type": "object",
+            "properties": {
+                "breaker": {
+                    "type": "string",
+                    "enum": [
+                        "closed",
+                        "open",
+                        "half_open"
+                    ],
+                    "example": "closed"
+                },
+                "host": {
+                    "type": "string",
+                    "example": "api.agify.io"
+                },
+                "quota_exhausted": {
+                    "type": "boolean",
+                    "example": false
+                },
+                "quota_limit": {
+                    "type": "integer",
+                    "example": 1000
+                },
+                "quota_remaining": {
+                    "type": "integer",
+                    "example": 742
+                },
+                "quota_reset_at": {
+                    "type": "string",
+                    "example": "2025-01-03T00:00:00Z"
+                },
+                "rate_limit": {
+                    "type": "number",
+                    "example": 5
+                }
+            }
         }
     }
 }`
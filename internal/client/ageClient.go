@@ -1,7 +1,14 @@
 package client
 
-import "context"
+import (
+	"context"
 
+	"github.com/ivanjabrony/personApi/internal/model"
+)
+
+// AgeClient estimates the age of a name, localized to countryId unless it is empty.
 type AgeClient interface {
-	GetAgeByName(ctx context.Context, name string) (*int, error)
+	GetAgeByName(ctx context.Context, name string, countryId string) (*model.AgeEstimate, error)
+	// GetAgesByNames answers in the order of names, nil for an unknown one.
+	GetAgesByNames(ctx context.Context, names []string, countryId string) ([]*model.AgeEstimate, error)
 }
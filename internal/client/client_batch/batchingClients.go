@@ -0,0 +1,91 @@
+package client_batch
+
+import (
+	"context"
+
+	"github.com/ivanjabrony/personApi/internal/client"
+	"github.com/ivanjabrony/personApi/internal/model"
+)
+
+// The batching clients send concurrent single name lookups to the wrapped
+// client as one batch. A zero Window turns batching off.
+
+type BatchingAgeClient struct {
+	next      client.AgeClient
+	collector *collector[model.AgeEstimate]
+}
+
+func NewBatchingAgeClient(next client.AgeClient, config Config) *BatchingAgeClient {
+	c := &BatchingAgeClient{next: next}
+	if config.Window > 0 {
+		c.collector = newCollector(config, next.GetAgesByNames)
+	}
+
+	return c
+}
+
+func (c *BatchingAgeClient) GetAgeByName(ctx context.Context, name string, countryId string) (*model.AgeEstimate, error) {
+	if c.collector == nil {
+		return c.next.GetAgeByName(ctx, name, countryId)
+	}
+
+	return c.collector.get(ctx, name, countryId)
+}
+
+func (c *BatchingAgeClient) GetAgesByNames(ctx context.Context, names []string, countryId string) ([]*model.AgeEstimate, error) {
+	return c.next.GetAgesByNames(ctx, names, countryId)
+}
+
+type BatchingGenderClient struct {
+	next      client.GenderClient
+	collector *collector[model.GenderEstimate]
+}
+
+func NewBatchingGenderClient(next client.GenderClient, config Config) *BatchingGenderClient {
+	c := &BatchingGenderClient{next: next}
+	if config.Window > 0 {
+		c.collector = newCollector(config, next.GetGendersByNames)
+	}
+
+	return c
+}
+
+func (c *BatchingGenderClient) GetGenderByName(ctx context.Context, name string, countryId string) (*model.GenderEstimate, error) {
+	if c.collector == nil {
+		return c.next.GetGenderByName(ctx, name, countryId)
+	}
+
+	return c.collector.get(ctx, name, countryId)
+}
+
+func (c *BatchingGenderClient) GetGendersByNames(ctx context.Context, names []string, countryId string) ([]*model.GenderEstimate, error) {
+	return c.next.GetGendersByNames(ctx, names, countryId)
+}
+
+type BatchingNationalityClient struct {
+	next      client.NationalityClient
+	collector *collector[model.NationalityEstimate]
+}
+
+func NewBatchingNationalityClient(next client.NationalityClient, config Config) *BatchingNationalityClient {
+	c := &BatchingNationalityClient{next: next}
+	if config.Window > 0 {
+		c.collector = newCollector(config, func(ctx context.Context, names []string, _ string) ([]*model.NationalityEstimate, error) {
+			return next.GetNationalitiesByNames(ctx, names)
+		})
+	}
+
+	return c
+}
+
+func (c *BatchingNationalityClient) GetNationalityByName(ctx context.Context, name string) (*model.NationalityEstimate, error) {
+	if c.collector == nil {
+		return c.next.GetNationalityByName(ctx, name)
+	}
+
+	return c.collector.get(ctx, name, "")
+}
+
+func (c *BatchingNationalityClient) GetNationalitiesByNames(ctx context.Context, names []string) ([]*model.NationalityEstimate, error) {
+	return c.next.GetNationalitiesByNames(ctx, names)
+}
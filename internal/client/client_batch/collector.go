@@ -0,0 +1,129 @@
+package client_batch
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+)
+
+type Config struct {
+	// Window is how long a lookup waits for others to share its request.
+	Window time.Duration
+	// MaxSize sends a batch right away once it has that many names.
+	MaxSize int
+}
+
+// collector coalesces lookups made within Window of each other into one
+// batch per country id.
+type collector[T any] struct {
+	window  time.Duration
+	maxSize int
+	fetch   func(ctx context.Context, names []string, countryId string) ([]*T, error)
+
+	mu      sync.Mutex
+	pending map[string]*batch[T]
+}
+
+type batch[T any] struct {
+	countryId string
+	names     []string
+	positions map[string]int
+	waiters   []context.Context
+	started   bool
+
+	done   chan struct{}
+	values []*T
+	err    error
+}
+
+func newCollector[T any](config Config, fetch func(context.Context, []string, string) ([]*T, error)) *collector[T] {
+	return &collector[T]{
+		window:  config.Window,
+		maxSize: max(config.MaxSize, 1),
+		fetch:   fetch,
+		pending: make(map[string]*batch[T]),
+	}
+}
+
+func (c *collector[T]) get(ctx context.Context, name string, countryId string) (*T, error) {
+	c.mu.Lock()
+	b, ok := c.pending[countryId]
+	if !ok {
+		b = &batch[T]{countryId: countryId, positions: make(map[string]int), done: make(chan struct{})}
+		c.pending[countryId] = b
+		time.AfterFunc(c.window, func() { c.send(b) })
+	}
+
+	position, ok := b.positions[name]
+	if !ok {
+		position = len(b.names)
+		b.positions[name] = position
+		b.names = append(b.names, name)
+	}
+	b.waiters = append(b.waiters, ctx)
+	full := len(b.names) >= c.maxSize
+	if full {
+		// later lookups start a new batch
+		delete(c.pending, countryId)
+	}
+	c.mu.Unlock()
+
+	if full {
+		go c.send(b)
+	}
+
+	select {
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	case <-b.done:
+		if b.err != nil {
+			return nil, b.err
+		}
+		return b.values[position], nil
+	}
+}
+
+// send fetches a batch once, whichever of the window and the size limit
+// comes first.
+func (c *collector[T]) send(b *batch[T]) {
+	c.mu.Lock()
+	if b.started {
+		c.mu.Unlock()
+		return
+	}
+	b.started = true
+	if c.pending[b.countryId] == b {
+		delete(c.pending, b.countryId)
+	}
+	ctx, cancel := batchContext(b.waiters)
+	c.mu.Unlock()
+	defer cancel()
+
+	values, err := c.fetch(ctx, b.names, b.countryId)
+	if err == nil && len(values) != len(b.names) {
+		err = fmt.Errorf("batch lookup answered %d names out of %d", len(values), len(b.names))
+	}
+
+	b.values, b.err = values, err
+	close(b.done)
+}
+
+// batchContext outlives any single waiter giving up, but keeps the latest
+// deadline of the waiters when all of them have one.
+func batchContext(waiters []context.Context) (context.Context, context.CancelFunc) {
+	ctx := context.WithoutCancel(waiters[0])
+
+	var latest time.Time
+	for _, waiter := range waiters {
+		deadline, ok := waiter.Deadline()
+		if !ok {
+			return context.WithCancel(ctx)
+		}
+		if deadline.After(latest) {
+			latest = deadline
+		}
+	}
+
+	return context.WithDeadline(ctx, latest)
+}
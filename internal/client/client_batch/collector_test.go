@@ -0,0 +1,260 @@
+package client_batch
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"sync"
+	"testing"
+	"time"
+)
+
+type lookup struct {
+	name      string
+	countryId string
+}
+
+// recordingFetch answers every name with "name@countryId" and remembers
+// the batches it was asked for.
+type recordingFetch struct {
+	mu      sync.Mutex
+	batches [][]string
+	err     error
+	short   bool
+}
+
+func (f *recordingFetch) fetch(_ context.Context, names []string, countryId string) ([]*string, error) {
+	f.mu.Lock()
+	f.batches = append(f.batches, slices.Clone(names))
+	f.mu.Unlock()
+
+	if f.err != nil {
+		return nil, f.err
+	}
+
+	values := make([]*string, len(names))
+	for i, name := range names {
+		value := name + "@" + countryId
+		values[i] = &value
+	}
+	if f.short {
+		values = values[1:]
+	}
+	return values, nil
+}
+
+func (f *recordingFetch) batchSizes() []int {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	sizes := make([]int, len(f.batches))
+	for i, batch := range f.batches {
+		sizes[i] = len(batch)
+	}
+	slices.Sort(sizes)
+	return sizes
+}
+
+func TestCollector(t *testing.T) {
+	tests := []struct {
+		name      string
+		config    Config
+		lookups   []lookup
+		wantSizes []int
+	}{
+		{
+			name:      "lookups within the window share a batch",
+			config:    Config{Window: 20 * time.Millisecond, MaxSize: 10},
+			lookups:   []lookup{{name: "anna"}, {name: "boris"}},
+			wantSizes: []int{2},
+		},
+		{
+			name:      "a full batch is sent without waiting for the window",
+			config:    Config{Window: time.Hour, MaxSize: 3},
+			lookups:   []lookup{{name: "anna"}, {name: "boris"}, {name: "vera"}},
+			wantSizes: []int{3},
+		},
+		{
+			name:      "a repeated name is asked once",
+			config:    Config{Window: 20 * time.Millisecond, MaxSize: 10},
+			lookups:   []lookup{{name: "anna"}, {name: "anna"}, {name: "boris"}},
+			wantSizes: []int{2},
+		},
+		{
+			name:      "names beyond the size start another batch",
+			config:    Config{Window: 20 * time.Millisecond, MaxSize: 2},
+			lookups:   []lookup{{name: "anna"}, {name: "boris"}, {name: "vera"}},
+			wantSizes: []int{1, 2},
+		},
+		{
+			name:      "countries are batched apart",
+			config:    Config{Window: 20 * time.Millisecond, MaxSize: 10},
+			lookups:   []lookup{{name: "anna", countryId: "RU"}, {name: "boris", countryId: "RU"}, {name: "anna", countryId: "US"}},
+			wantSizes: []int{1, 2},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fetch := &recordingFetch{}
+			c := newCollector(tt.config, fetch.fetch)
+
+			ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+			defer cancel()
+
+			var wg sync.WaitGroup
+			for _, l := range tt.lookups {
+				wg.Add(1)
+				go func() {
+					defer wg.Done()
+
+					value, err := c.get(ctx, l.name, l.countryId)
+					if err != nil {
+						t.Errorf("get(%s, %s) error = %v", l.name, l.countryId, err)
+						return
+					}
+					if want := l.name + "@" + l.countryId; *value != want {
+						t.Errorf("get(%s, %s) = %s, want %s", l.name, l.countryId, *value, want)
+					}
+				}()
+			}
+			wg.Wait()
+
+			if got := fetch.batchSizes(); !slices.Equal(got, tt.wantSizes) {
+				t.Errorf("batch sizes = %v, want %v", got, tt.wantSizes)
+			}
+		})
+	}
+}
+
+func TestCollectorWaitsForTheWindow(t *testing.T) {
+	window := 50 * time.Millisecond
+	fetch := &recordingFetch{}
+	c := newCollector(Config{Window: window, MaxSize: 10}, fetch.fetch)
+
+	start := time.Now()
+	if _, err := c.get(context.Background(), "anna", ""); err != nil {
+		t.Fatalf("get() error = %v", err)
+	}
+	if elapsed := time.Since(start); elapsed < window {
+		t.Errorf("batch was sent after %s, before the window of %s", elapsed, window)
+	}
+}
+
+func TestCollectorErrors(t *testing.T) {
+	upstreamErr := errors.New("upstream failed")
+
+	tests := []struct {
+		name  string
+		fetch *recordingFetch
+		want  error
+	}{
+		{name: "fetch error reaches every waiter", fetch: &recordingFetch{err: upstreamErr}, want: upstreamErr},
+		{name: "short answer is an error", fetch: &recordingFetch{short: true}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := newCollector(Config{Window: time.Hour, MaxSize: 2}, tt.fetch.fetch)
+
+			var wg sync.WaitGroup
+			for _, name := range []string{"anna", "boris"} {
+				wg.Add(1)
+				go func() {
+					defer wg.Done()
+
+					_, err := c.get(context.Background(), name, "")
+					if err == nil {
+						t.Errorf("get(%s) succeeded, want an error", name)
+					}
+					if tt.want != nil && !errors.Is(err, tt.want) {
+						t.Errorf("get(%s) error = %v, want %v", name, err, tt.want)
+					}
+				}()
+			}
+			wg.Wait()
+		})
+	}
+}
+
+func TestCollectorCancelledWaiterDoesNotCancelTheBatch(t *testing.T) {
+	release := make(chan struct{})
+	c := newCollector(Config{Window: time.Hour, MaxSize: 2}, func(ctx context.Context, names []string, _ string) ([]*string, error) {
+		select {
+		case <-release:
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		}
+
+		values := make([]*string, len(names))
+		for i := range names {
+			values[i] = &names[i]
+		}
+		return values, nil
+	})
+
+	first, cancel := context.WithCancel(context.Background())
+	firstDone := make(chan error, 1)
+	go func() {
+		_, err := c.get(first, "anna", "")
+		firstDone <- err
+	}()
+
+	secondDone := make(chan error, 1)
+	go func() {
+		_, err := c.get(context.Background(), "boris", "")
+		secondDone <- err
+	}()
+
+	// the second name filled the batch, which is waiting in fetch by now
+	time.Sleep(20 * time.Millisecond)
+	cancel()
+	if err := <-firstDone; !errors.Is(err, context.Canceled) {
+		t.Fatalf("cancelled get() error = %v, want %v", err, context.Canceled)
+	}
+
+	close(release)
+	if err := <-secondDone; err != nil {
+		t.Fatalf("get() error = %v after another waiter gave up", err)
+	}
+}
+
+func TestBatchContext(t *testing.T) {
+	soon := time.Now().Add(time.Minute)
+	later := time.Now().Add(time.Hour)
+
+	withDeadline := func(deadline time.Time) context.Context {
+		ctx, cancel := context.WithDeadline(context.Background(), deadline)
+		t.Cleanup(cancel)
+		return ctx
+	}
+
+	tests := []struct {
+		name         string
+		waiters      []context.Context
+		wantDeadline time.Time
+	}{
+		{name: "latest deadline of all waiters", waiters: []context.Context{withDeadline(soon), withDeadline(later)}, wantDeadline: later},
+		{name: "a waiter without deadline lifts it", waiters: []context.Context{withDeadline(soon), context.Background()}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx, cancel := batchContext(tt.waiters)
+			defer cancel()
+
+			deadline, ok := ctx.Deadline()
+			if ok != !tt.wantDeadline.IsZero() || !deadline.Equal(tt.wantDeadline) {
+				t.Errorf("deadline = %s, %t, want %s", deadline, ok, tt.wantDeadline)
+			}
+		})
+	}
+
+	cancelled, cancel := context.WithCancel(context.Background())
+	cancel()
+	ctx, stop := batchContext([]context.Context{cancelled})
+	defer stop()
+	if ctx.Err() != nil {
+		t.Errorf("batch context of a cancelled waiter is done: %v", ctx.Err())
+	}
+}
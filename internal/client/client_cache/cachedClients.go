@@ -0,0 +1,73 @@
+package client_cache
+
+import (
+	"context"
+	"log/slog"
+
+	"github.com/ivanjabrony/personApi/internal/client"
+	"github.com/ivanjabrony/personApi/internal/model"
+	"github.com/ivanjabrony/personApi/internal/repository"
+)
+
+type CachedAgeClient struct {
+	next  client.AgeClient
+	cache *lookupCache[model.AgeEstimate]
+}
+
+func NewCachedAgeClient(next client.AgeClient, config Config, store repository.EnrichmentCacheRepository, logger *slog.Logger) *CachedAgeClient {
+	return &CachedAgeClient{next: next, cache: newLookupCache[model.AgeEstimate](model.EnrichmentKindAge, config, store, logger)}
+}
+
+func (c *CachedAgeClient) GetAgeByName(ctx context.Context, name string, countryId string) (*model.AgeEstimate, error) {
+	return c.cache.get(ctx, name, countryId, func(ctx context.Context) (*model.AgeEstimate, error) {
+		return c.next.GetAgeByName(ctx, name, countryId)
+	})
+}
+
+func (c *CachedAgeClient) GetAgesByNames(ctx context.Context, names []string, countryId string) ([]*model.AgeEstimate, error) {
+	return c.cache.getMany(ctx, names, countryId, func(ctx context.Context, missing []string) ([]*model.AgeEstimate, error) {
+		return c.next.GetAgesByNames(ctx, missing, countryId)
+	})
+}
+
+type CachedGenderClient struct {
+	next  client.GenderClient
+	cache *lookupCache[model.GenderEstimate]
+}
+
+func NewCachedGenderClient(next client.GenderClient, config Config, store repository.EnrichmentCacheRepository, logger *slog.Logger) *CachedGenderClient {
+	return &CachedGenderClient{next: next, cache: newLookupCache[model.GenderEstimate](model.EnrichmentKindGender, config, store, logger)}
+}
+
+func (c *CachedGenderClient) GetGenderByName(ctx context.Context, name string, countryId string) (*model.GenderEstimate, error) {
+	return c.cache.get(ctx, name, countryId, func(ctx context.Context) (*model.GenderEstimate, error) {
+		return c.next.GetGenderByName(ctx, name, countryId)
+	})
+}
+
+func (c *CachedGenderClient) GetGendersByNames(ctx context.Context, names []string, countryId string) ([]*model.GenderEstimate, error) {
+	return c.cache.getMany(ctx, names, countryId, func(ctx context.Context, missing []string) ([]*model.GenderEstimate, error) {
+		return c.next.GetGendersByNames(ctx, missing, countryId)
+	})
+}
+
+type CachedNationalityClient struct {
+	next  client.NationalityClient
+	cache *lookupCache[model.NationalityEstimate]
+}
+
+func NewCachedNationalityClient(next client.NationalityClient, config Config, store repository.EnrichmentCacheRepository, logger *slog.Logger) *CachedNationalityClient {
+	return &CachedNationalityClient{next: next, cache: newLookupCache[model.NationalityEstimate](model.EnrichmentKindNationality, config, store, logger)}
+}
+
+func (c *CachedNationalityClient) GetNationalityByName(ctx context.Context, name string) (*model.NationalityEstimate, error) {
+	return c.cache.get(ctx, name, "", func(ctx context.Context) (*model.NationalityEstimate, error) {
+		return c.next.GetNationalityByName(ctx, name)
+	})
+}
+
+func (c *CachedNationalityClient) GetNationalitiesByNames(ctx context.Context, names []string) ([]*model.NationalityEstimate, error) {
+	return c.cache.getMany(ctx, names, "", func(ctx context.Context, missing []string) ([]*model.NationalityEstimate, error) {
+		return c.next.GetNationalitiesByNames(ctx, missing)
+	})
+}
@@ -0,0 +1,199 @@
+package client_cache
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"strings"
+	"time"
+
+	"github.com/ivanjabrony/personApi/internal/repository"
+	"golang.org/x/sync/singleflight"
+)
+
+type Config struct {
+	TTL     time.Duration
+	MaxSize int
+	// FetchTimeout bounds a lookup shared by concurrent callers, it runs
+	// on even when the caller that started it gives up. Zero leaves it
+	// to the client's own timeout.
+	FetchTimeout time.Duration
+}
+
+// lookupCache memoizes name lookups, including the ones that found nothing,
+// in memory and optionally in a persistent store shared between restarts.
+// Concurrent lookups of the same name are collapsed into one upstream call.
+type lookupCache[T any] struct {
+	kind         string
+	ttl          time.Duration
+	fetchTimeout time.Duration
+	memory       *lruCache[*T]
+	store        repository.EnrichmentCacheRepository
+	group        singleflight.Group
+	logger       *slog.Logger
+}
+
+func newLookupCache[T any](kind string, config Config, store repository.EnrichmentCacheRepository, logger *slog.Logger) *lookupCache[T] {
+	return &lookupCache[T]{
+		kind:         kind,
+		ttl:          config.TTL,
+		fetchTimeout: config.FetchTimeout,
+		memory:       newLruCache[*T](config.MaxSize),
+		store:        store,
+		logger:       logger,
+	}
+}
+
+func (c *lookupCache[T]) get(ctx context.Context, name string, countryId string, fetch func(context.Context) (*T, error)) (*T, error) {
+	key := lookupKey(name, countryId)
+
+	if value, ok := c.memory.get(key); ok {
+		return value, nil
+	}
+
+	result := c.group.DoChan(key, func() (any, error) {
+		// the callers that joined must not fail because the first one gave up
+		ctx, cancel := c.sharedContext(ctx)
+		defer cancel()
+
+		if value, ok := c.loadPersisted(ctx, key); ok {
+			c.memory.set(key, value, time.Now().Add(c.ttl))
+			return value, nil
+		}
+
+		value, err := fetch(ctx)
+		if err != nil {
+			return nil, err
+		}
+
+		expiresAt := time.Now().Add(c.ttl)
+		c.memory.set(key, value, expiresAt)
+		c.persist(ctx, key, value, expiresAt)
+
+		return value, nil
+	})
+
+	select {
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	case res := <-result:
+		if res.Err != nil {
+			return nil, res.Err
+		}
+		return res.Val.(*T), nil
+	}
+}
+
+// getMany answers names from the cache and fetches the rest in one call,
+// each missing name once. Batches are not collapsed with concurrent lookups.
+func (c *lookupCache[T]) getMany(ctx context.Context, names []string, countryId string, fetch func(context.Context, []string) ([]*T, error)) ([]*T, error) {
+	values := make([]*T, len(names))
+	missing := make(map[string][]int)
+	var missingNames []string
+
+	for i, name := range names {
+		key := lookupKey(name, countryId)
+
+		if value, ok := c.memory.get(key); ok {
+			values[i] = value
+			continue
+		}
+		if value, ok := c.loadPersisted(ctx, key); ok {
+			c.memory.set(key, value, time.Now().Add(c.ttl))
+			values[i] = value
+			continue
+		}
+
+		if _, ok := missing[key]; !ok {
+			missingNames = append(missingNames, name)
+		}
+		missing[key] = append(missing[key], i)
+	}
+
+	if len(missingNames) == 0 {
+		return values, nil
+	}
+
+	fetched, err := fetch(ctx, missingNames)
+	if err != nil {
+		return nil, err
+	}
+	if len(fetched) != len(missingNames) {
+		return nil, fmt.Errorf("%s lookup answered %d names out of %d", c.kind, len(fetched), len(missingNames))
+	}
+
+	expiresAt := time.Now().Add(c.ttl)
+	for i, name := range missingNames {
+		key := lookupKey(name, countryId)
+		c.memory.set(key, fetched[i], expiresAt)
+		c.persist(ctx, key, fetched[i], expiresAt)
+
+		for _, position := range missing[key] {
+			values[position] = fetched[i]
+		}
+	}
+
+	return values, nil
+}
+
+// sharedContext keeps the values of ctx but neither its cancellation nor its
+// deadline, the shared lookup is bounded by fetchTimeout instead.
+func (c *lookupCache[T]) sharedContext(ctx context.Context) (context.Context, context.CancelFunc) {
+	shared := context.WithoutCancel(ctx)
+	if c.fetchTimeout <= 0 {
+		return context.WithCancel(shared)
+	}
+
+	return context.WithTimeout(shared, c.fetchTimeout)
+}
+
+func (c *lookupCache[T]) loadPersisted(ctx context.Context, key string) (*T, bool) {
+	if c.store == nil {
+		return nil, false
+	}
+
+	data, found, err := c.store.Get(ctx, c.kind, key)
+	if err != nil {
+		c.logger.Warn("Couldn't read persisted enrichment cache", slog.String("Kind", c.kind), slog.String("Error", err.Error()))
+		return nil, false
+	}
+	if !found {
+		return nil, false
+	}
+
+	var value *T
+	if err := json.Unmarshal(data, &value); err != nil {
+		c.logger.Warn("Couldn't decode persisted enrichment cache", slog.String("Kind", c.kind), slog.String("Error", err.Error()))
+		return nil, false
+	}
+
+	return value, true
+}
+
+func (c *lookupCache[T]) persist(ctx context.Context, key string, value *T, expiresAt time.Time) {
+	if c.store == nil {
+		return
+	}
+
+	data, err := json.Marshal(value)
+	if err != nil {
+		c.logger.Warn("Couldn't encode enrichment cache entry", slog.String("Kind", c.kind), slog.String("Error", err.Error()))
+		return
+	}
+
+	if err := c.store.Set(ctx, c.kind, key, data, expiresAt); err != nil {
+		c.logger.Warn("Couldn't persist enrichment cache entry", slog.String("Kind", c.kind), slog.String("Error", err.Error()))
+	}
+}
+
+// lookupKey identifies a lookup, localized lookups of a name are kept apart
+// from the generic one.
+func lookupKey(name string, countryId string) string {
+	key := strings.ToLower(strings.TrimSpace(name))
+	if countryId != "" {
+		key += "@" + strings.ToUpper(countryId)
+	}
+
+	return key
+}
@@ -0,0 +1,87 @@
+package client_cache
+
+import (
+	"context"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestGetCollapsesConcurrentMisses(t *testing.T) {
+	cache := newLookupCache[int]("age", Config{TTL: time.Hour}, nil, nil)
+
+	var calls atomic.Int32
+	release := make(chan struct{})
+	fetch := func(context.Context) (*int, error) {
+		calls.Add(1)
+		<-release
+		value := 42
+		return &value, nil
+	}
+
+	const callers = 10
+	var wg sync.WaitGroup
+	values := make([]*int, callers)
+	errs := make([]error, callers)
+	for i := range callers {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			values[i], errs[i] = cache.get(context.Background(), "Ann", "", fetch)
+		}()
+	}
+	close(release)
+	wg.Wait()
+
+	if got := calls.Load(); got != 1 {
+		t.Errorf("fetch called %d times, want 1", got)
+	}
+	for i := range callers {
+		if errs[i] != nil || values[i] == nil || *values[i] != 42 {
+			t.Errorf("caller %d got %v, %v", i, values[i], errs[i])
+		}
+	}
+}
+
+func TestGetFetchesAgainAfterTTL(t *testing.T) {
+	cache := newLookupCache[int]("age", Config{TTL: time.Millisecond}, nil, nil)
+
+	var calls int
+	fetch := func(context.Context) (*int, error) {
+		calls++
+		return &calls, nil
+	}
+
+	if _, err := cache.get(context.Background(), "Ann", "", fetch); err != nil {
+		t.Fatalf("get() error = %v", err)
+	}
+	time.Sleep(5 * time.Millisecond)
+	if _, err := cache.get(context.Background(), "Ann", "", fetch); err != nil {
+		t.Fatalf("get() error = %v", err)
+	}
+
+	if calls != 2 {
+		t.Errorf("fetch called %d times, want 2", calls)
+	}
+}
+
+func TestGetKeepsLocalizedLookupsApart(t *testing.T) {
+	cache := newLookupCache[int]("age", Config{TTL: time.Hour}, nil, nil)
+
+	var calls int
+	fetch := func(context.Context) (*int, error) {
+		calls++
+		return &calls, nil
+	}
+
+	for _, lookup := range []struct{ name, countryId string }{{"Ann", ""}, {" ann ", ""}, {"Ann", "us"}, {"ANN", "US"}} {
+		if _, err := cache.get(context.Background(), lookup.name, lookup.countryId, fetch); err != nil {
+			t.Fatalf("get() error = %v", err)
+		}
+	}
+
+	if calls != 2 {
+		t.Errorf("fetch called %d times, want 2", calls)
+	}
+}
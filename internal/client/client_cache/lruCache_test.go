@@ -0,0 +1,44 @@
+package client_cache
+
+import (
+	"testing"
+	"time"
+)
+
+func TestLruCacheEvictsLeastRecentlyUsed(t *testing.T) {
+	cache := newLruCache[int](2)
+	expiresAt := time.Now().Add(time.Hour)
+
+	cache.set("a", 1, expiresAt)
+	cache.set("b", 2, expiresAt)
+	if _, ok := cache.get("a"); !ok {
+		t.Fatal("get(a) missed before eviction")
+	}
+	cache.set("c", 3, expiresAt)
+
+	if _, ok := cache.get("b"); ok {
+		t.Error("get(b) hit, want it evicted")
+	}
+	for key, want := range map[string]int{"a": 1, "c": 3} {
+		if got, ok := cache.get(key); !ok || got != want {
+			t.Errorf("get(%s) = %d, %t, want %d", key, got, ok, want)
+		}
+	}
+}
+
+func TestLruCacheExpires(t *testing.T) {
+	cache := newLruCache[int](0)
+
+	cache.set("expired", 1, time.Now().Add(-time.Second))
+	cache.set("fresh", 2, time.Now().Add(time.Hour))
+
+	if _, ok := cache.get("expired"); ok {
+		t.Error("get(expired) hit")
+	}
+	if _, ok := cache.get("fresh"); !ok {
+		t.Error("get(fresh) missed")
+	}
+	if cache.order.Len() != 1 {
+		t.Errorf("cache holds %d entries, want the expired one dropped", cache.order.Len())
+	}
+}
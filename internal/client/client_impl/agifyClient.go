@@ -2,49 +2,49 @@ package client_impl
 
 import (
 	"context"
-	"encoding/json"
-	"fmt"
 	"net/http"
+
+	"github.com/ivanjabrony/personApi/internal/model"
 )
 
 type AgifyClient struct {
-	BaseURL string
+	BaseURL    string
+	APIKey     string
+	HTTPClient *http.Client
 }
 
 type AgifyResponse struct {
-	Name  string `json:"name"`
-	Age   int    `json:"age"`
-	Count int    `json:"count"`
+	Name      string `json:"name"`
+	Age       *int   `json:"age"`
+	Count     int    `json:"count"`
+	CountryID string `json:"country_id"`
 }
 
-func NewAgifyClient() *AgifyClient {
+func NewAgifyClient(httpClient *http.Client, apiKey string) *AgifyClient {
 	return &AgifyClient{
-		BaseURL: "https://api.agify.io/",
+		BaseURL:    "https://api.agify.io/",
+		APIKey:     apiKey,
+		HTTPClient: httpClient,
 	}
 }
 
-func (c *AgifyClient) GetAgeByName(ctx context.Context, name string) (*int, error) {
-	url := fmt.Sprintf("%s?name=%s", c.BaseURL, name)
-
-	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
-	if err != nil {
-		return nil, fmt.Errorf("failed to create request to agify.io: %w", err)
+func (c *AgifyClient) GetAgeByName(ctx context.Context, name string, countryId string) (*model.AgeEstimate, error) {
+	var result AgifyResponse
+	if err := getJSON(ctx, c.HTTPClient, "agify.io", c.BaseURL+"?"+lookupQuery(name, countryId, c.APIKey), c.APIKey, &result); err != nil {
+		return nil, err
 	}
 
-	resp, err := http.DefaultClient.Do(req)
-	if err != nil {
-		return nil, fmt.Errorf("failed to request agify.io: %w", err)
-	}
-	defer resp.Body.Close()
+	return result.estimate(), nil
+}
 
-	if resp.StatusCode != http.StatusOK {
-		return nil, fmt.Errorf("%s returned status: %d", c.BaseURL, resp.StatusCode)
-	}
+func (c *AgifyClient) GetAgesByNames(ctx context.Context, names []string, countryId string) ([]*model.AgeEstimate, error) {
+	return getBatch(ctx, c.HTTPClient, "agify.io", c.BaseURL, names, countryId, c.APIKey, AgifyResponse.estimate)
+}
 
-	var result AgifyResponse
-	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
-		return nil, fmt.Errorf("failed to parse %s response: %w", c.BaseURL, err)
+func (r AgifyResponse) estimate() *model.AgeEstimate {
+	if r.Age == nil {
+		return nil
 	}
 
-	return &result.Age, nil
+	return &model.AgeEstimate{Age: *r.Age, Count: r.Count, CountryId: r.CountryID}
 }
@@ -2,49 +2,50 @@ package client_impl
 
 import (
 	"context"
-	"encoding/json"
-	"fmt"
 	"net/http"
+
+	"github.com/ivanjabrony/personApi/internal/model"
 )
 
 type GenderizeClient struct {
-	BaseURL string
+	BaseURL    string
+	APIKey     string
+	HTTPClient *http.Client
 }
 
 type GenderizeResponse struct {
 	Count       int     `json:"count"`
 	Name        string  `json:"name"`
-	Gender      string  `json:"gender"`
+	Gender      *string `json:"gender"`
 	Probability float64 `json:"probability"`
+	CountryID   string  `json:"country_id"`
 }
 
-func NewGenderizeClient() *GenderizeClient {
+func NewGenderizeClient(httpClient *http.Client, apiKey string) *GenderizeClient {
 	return &GenderizeClient{
-		BaseURL: "https://api.genderize.io/",
+		BaseURL:    "https://api.genderize.io/",
+		APIKey:     apiKey,
+		HTTPClient: httpClient,
 	}
 }
 
-func (c *GenderizeClient) GetGenderByName(ctx context.Context, name string) (*string, error) {
-	url := fmt.Sprintf("%s?name=%s", c.BaseURL, name)
-	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
-	if err != nil {
-		return nil, fmt.Errorf("failed to create request to agify.io: %w", err)
+func (c *GenderizeClient) GetGenderByName(ctx context.Context, name string, countryId string) (*model.GenderEstimate, error) {
+	var result GenderizeResponse
+	if err := getJSON(ctx, c.HTTPClient, "genderize.io", c.BaseURL+"?"+lookupQuery(name, countryId, c.APIKey), c.APIKey, &result); err != nil {
+		return nil, err
 	}
 
-	resp, err := http.DefaultClient.Do(req)
-	if err != nil {
-		return nil, fmt.Errorf("failed to request agify.io: %w", err)
-	}
-	defer resp.Body.Close()
+	return result.estimate(), nil
+}
 
-	if resp.StatusCode != http.StatusOK {
-		return nil, fmt.Errorf("%s returned status: %d", c.BaseURL, resp.StatusCode)
-	}
+func (c *GenderizeClient) GetGendersByNames(ctx context.Context, names []string, countryId string) ([]*model.GenderEstimate, error) {
+	return getBatch(ctx, c.HTTPClient, "genderize.io", c.BaseURL, names, countryId, c.APIKey, GenderizeResponse.estimate)
+}
 
-	var result GenderizeResponse
-	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
-		return nil, fmt.Errorf("failed to parse %s response: %w", c.BaseURL, err)
+func (r GenderizeResponse) estimate() *model.GenderEstimate {
+	if r.Gender == nil {
+		return nil
 	}
 
-	return &result.Gender, nil
+	return &model.GenderEstimate{Gender: *r.Gender, Probability: r.Probability, Count: r.Count, CountryId: r.CountryID}
 }
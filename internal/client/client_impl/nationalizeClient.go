@@ -2,13 +2,15 @@ package client_impl
 
 import (
 	"context"
-	"encoding/json"
-	"fmt"
 	"net/http"
+
+	"github.com/ivanjabrony/personApi/internal/model"
 )
 
 type NationalizeClient struct {
-	BaseURL string
+	BaseURL    string
+	APIKey     string
+	HTTPClient *http.Client
 }
 
 type NationalizeResponse struct {
@@ -20,43 +22,36 @@ type NationalizeResponse struct {
 	} `json:"country"`
 }
 
-func NewNationalityClient() *NationalizeClient {
+func NewNationalityClient(httpClient *http.Client, apiKey string) *NationalizeClient {
 	return &NationalizeClient{
-		BaseURL: "https://api.nationalize.io/",
+		BaseURL:    "https://api.nationalize.io/",
+		APIKey:     apiKey,
+		HTTPClient: httpClient,
 	}
 }
 
-func (c *NationalizeClient) GetNationalityByName(ctx context.Context, name string) (*string, error) {
-
-	url := fmt.Sprintf("%s?name=%s", c.BaseURL, name)
-	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
-	if err != nil {
-		return nil, fmt.Errorf("failed to create request to agify.io: %w", err)
+func (c *NationalizeClient) GetNationalityByName(ctx context.Context, name string) (*model.NationalityEstimate, error) {
+	var result NationalizeResponse
+	if err := getJSON(ctx, c.HTTPClient, "nationalize.io", c.BaseURL+"?"+lookupQuery(name, "", c.APIKey), c.APIKey, &result); err != nil {
+		return nil, err
 	}
 
-	resp, err := http.DefaultClient.Do(req)
-	if err != nil {
-		return nil, fmt.Errorf("failed to request agify.io: %w", err)
-	}
-	defer resp.Body.Close()
+	return result.estimate(), nil
+}
 
-	if resp.StatusCode != http.StatusOK {
-		return nil, fmt.Errorf("%s returned status: %d", c.BaseURL, resp.StatusCode)
-	}
+func (c *NationalizeClient) GetNationalitiesByNames(ctx context.Context, names []string) ([]*model.NationalityEstimate, error) {
+	return getBatch(ctx, c.HTTPClient, "nationalize.io", c.BaseURL, names, "", c.APIKey, NationalizeResponse.estimate)
+}
 
-	var result NationalizeResponse
-	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
-		return nil, fmt.Errorf("failed to parse %s response: %w", c.BaseURL, err)
+func (r NationalizeResponse) estimate() *model.NationalityEstimate {
+	if len(r.Country) == 0 {
+		return nil
 	}
 
-	var country *string
-	curProb := 0.0
-
-	for _, v := range result.Country {
-		if curProb <= v.Probability {
-			country = &v.CountryID
-		}
+	countries := make([]model.CountryProbability, len(r.Country))
+	for i, v := range r.Country {
+		countries[i] = model.CountryProbability{CountryId: v.CountryID, Probability: v.Probability}
 	}
 
-	return country, nil
+	return model.NewNationalityEstimate(countries, r.Count)
 }
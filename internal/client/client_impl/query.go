@@ -0,0 +1,51 @@
+package client_impl
+
+import (
+	"errors"
+	"net/url"
+	"strings"
+)
+
+// lookupQuery builds the query string shared by agify, genderize and
+// nationalize, country_id and apikey are only sent when set.
+func lookupQuery(name string, countryId string, apiKey string) string {
+	query := url.Values{}
+	query.Set("name", name)
+	if countryId != "" {
+		query.Set("country_id", countryId)
+	}
+	if apiKey != "" {
+		query.Set("apikey", apiKey)
+	}
+
+	return query.Encode()
+}
+
+// batchQuery is lookupQuery for several names at once.
+func batchQuery(names []string, countryId string, apiKey string) string {
+	query := url.Values{}
+	query["name[]"] = names
+	if countryId != "" {
+		query.Set("country_id", countryId)
+	}
+	if apiKey != "" {
+		query.Set("apikey", apiKey)
+	}
+
+	return query.Encode()
+}
+
+// redactAPIKey keeps the api key out of errors, a failed request reports
+// its full URL.
+func redactAPIKey(err error, apiKey string) error {
+	var urlErr *url.Error
+	if apiKey == "" || !errors.As(err, &urlErr) {
+		return err
+	}
+
+	return &url.Error{
+		Op:  urlErr.Op,
+		URL: strings.ReplaceAll(urlErr.URL, url.QueryEscape(apiKey), "REDACTED"),
+		Err: urlErr.Err,
+	}
+}
@@ -0,0 +1,75 @@
+package client_impl
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+
+	"github.com/ivanjabrony/personApi/internal/model"
+)
+
+// MaxBatchSize is the amount of names agify, genderize and nationalize
+// accept in one request.
+const MaxBatchSize = 10
+
+// getJSON requests url and decodes the JSON answer into result. 429 and 5xx
+// answers are reported as ErrUpstreamUnavailable.
+func getJSON(ctx context.Context, httpClient *http.Client, upstream string, url string, apiKey string, result any) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return fmt.Errorf("failed to create request to %s: %w", upstream, err)
+	}
+
+	resp, err := httpClient.Do(req)
+	if err != nil {
+		err = redactAPIKey(err, apiKey)
+		return fmt.Errorf("%w: failed to request %s: %w", model.ErrUpstreamUnavailable, upstream, err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode == http.StatusTooManyRequests || resp.StatusCode >= http.StatusInternalServerError {
+		return fmt.Errorf("%w: %s returned status: %d", model.ErrUpstreamUnavailable, upstream, resp.StatusCode)
+	}
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("%s returned status: %d", upstream, resp.StatusCode)
+	}
+
+	if err := json.NewDecoder(resp.Body).Decode(result); err != nil {
+		return fmt.Errorf("failed to parse %s response: %w", upstream, err)
+	}
+
+	return nil
+}
+
+// getBatch looks names up MaxBatchSize at a time, the answers come back in
+// the order of names.
+func getBatch[R any, T any](
+	ctx context.Context,
+	httpClient *http.Client,
+	upstream string,
+	baseURL string,
+	names []string,
+	countryId string,
+	apiKey string,
+	estimate func(R) *T) ([]*T, error) {
+	estimates := make([]*T, 0, len(names))
+
+	for start := 0; start < len(names); start += MaxBatchSize {
+		chunk := names[start:min(start+MaxBatchSize, len(names))]
+
+		var results []R
+		if err := getJSON(ctx, httpClient, upstream, baseURL+"?"+batchQuery(chunk, countryId, apiKey), apiKey, &results); err != nil {
+			return nil, err
+		}
+		if len(results) != len(chunk) {
+			return nil, fmt.Errorf("%s answered %d names out of %d", upstream, len(results), len(chunk))
+		}
+
+		for _, result := range results {
+			estimates = append(estimates, estimate(result))
+		}
+	}
+
+	return estimates, nil
+}
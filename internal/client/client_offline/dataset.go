@@ -0,0 +1,115 @@
+package client_offline
+
+import (
+	_ "embed"
+	"encoding/csv"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"strings"
+
+	"github.com/ivanjabrony/personApi/internal/model"
+)
+
+//go:embed names.csv
+var bundledDataset string
+
+type nameStatistics struct {
+	age         *model.AgeEstimate
+	gender      *model.GenderEstimate
+	nationality *model.NationalityEstimate
+}
+
+// Dataset holds name statistics loaded once and answered from memory. Names
+// are matched case-insensitively, a missing name is unknown, not an error.
+type Dataset struct {
+	names map[string]nameStatistics
+}
+
+// LoadDataset reads the CSV at path, or the bundled dataset when path is empty.
+func LoadDataset(path string) (*Dataset, error) {
+	if path == "" {
+		return parseDataset(strings.NewReader(bundledDataset))
+	}
+
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open name dataset: %w", err)
+	}
+	defer file.Close()
+
+	return parseDataset(file)
+}
+
+// parseDataset reads rows of name,count,age,gender,gender_probability,nationality
+// where nationality is a space separated list of COUNTRY:probability pairs.
+// Empty cells leave that estimate unknown.
+func parseDataset(reader io.Reader) (*Dataset, error) {
+	records, err := csv.NewReader(reader).ReadAll()
+	if err != nil {
+		return nil, fmt.Errorf("failed to read name dataset: %w", err)
+	}
+	if len(records) == 0 {
+		return nil, fmt.Errorf("name dataset has no header")
+	}
+
+	dataset := &Dataset{names: make(map[string]nameStatistics, len(records)-1)}
+	for i, record := range records[1:] {
+		statistics, err := parseRecord(record)
+		if err != nil {
+			return nil, fmt.Errorf("name dataset line %d: %w", i+2, err)
+		}
+		dataset.names[datasetKey(record[0])] = statistics
+	}
+
+	return dataset, nil
+}
+
+func parseRecord(record []string) (nameStatistics, error) {
+	var statistics nameStatistics
+
+	if len(record) != 6 {
+		return statistics, fmt.Errorf("expected 6 fields, got %d", len(record))
+	}
+
+	count, err := strconv.Atoi(record[1])
+	if err != nil {
+		return statistics, fmt.Errorf("invalid count %q", record[1])
+	}
+
+	if record[2] != "" {
+		age, err := strconv.Atoi(record[2])
+		if err != nil {
+			return statistics, fmt.Errorf("invalid age %q", record[2])
+		}
+		statistics.age = &model.AgeEstimate{Age: age, Count: count}
+	}
+
+	if record[3] != "" {
+		probability, err := strconv.ParseFloat(record[4], 64)
+		if err != nil {
+			return statistics, fmt.Errorf("invalid gender probability %q", record[4])
+		}
+		statistics.gender = &model.GenderEstimate{Gender: record[3], Probability: probability, Count: count}
+	}
+
+	if record[5] != "" {
+		var countries []model.CountryProbability
+		for _, pair := range strings.Fields(record[5]) {
+			countryId, value, ok := strings.Cut(pair, ":")
+			probability, err := strconv.ParseFloat(value, 64)
+			if !ok || err != nil {
+				return statistics, fmt.Errorf("invalid nationality %q", pair)
+			}
+			countries = append(countries, model.CountryProbability{CountryId: countryId, Probability: probability})
+		}
+		statistics.nationality = model.NewNationalityEstimate(countries, count)
+	}
+
+	return statistics, nil
+}
+
+func datasetKey(name string) string {
+	return strings.ToLower(strings.TrimSpace(name))
+}
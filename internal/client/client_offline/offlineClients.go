@@ -0,0 +1,91 @@
+package client_offline
+
+import (
+	"context"
+
+	"github.com/ivanjabrony/personApi/internal/model"
+)
+
+// The offline clients answer from a Dataset without any network access. The
+// dataset is not localized, so a country id is ignored.
+
+type OfflineAgeClient struct {
+	dataset *Dataset
+}
+
+func NewOfflineAgeClient(dataset *Dataset) *OfflineAgeClient {
+	return &OfflineAgeClient{dataset}
+}
+
+func (c *OfflineAgeClient) GetAgeByName(ctx context.Context, name string, countryId string) (*model.AgeEstimate, error) {
+	age := c.dataset.names[datasetKey(name)].age
+	if age == nil {
+		return nil, nil
+	}
+
+	estimate := *age
+	return &estimate, nil
+}
+
+func (c *OfflineAgeClient) GetAgesByNames(ctx context.Context, names []string, countryId string) ([]*model.AgeEstimate, error) {
+	return lookupEach(ctx, names, countryId, c.GetAgeByName)
+}
+
+type OfflineGenderClient struct {
+	dataset *Dataset
+}
+
+func NewOfflineGenderClient(dataset *Dataset) *OfflineGenderClient {
+	return &OfflineGenderClient{dataset}
+}
+
+func (c *OfflineGenderClient) GetGenderByName(ctx context.Context, name string, countryId string) (*model.GenderEstimate, error) {
+	gender := c.dataset.names[datasetKey(name)].gender
+	if gender == nil {
+		return nil, nil
+	}
+
+	estimate := *gender
+	return &estimate, nil
+}
+
+func (c *OfflineGenderClient) GetGendersByNames(ctx context.Context, names []string, countryId string) ([]*model.GenderEstimate, error) {
+	return lookupEach(ctx, names, countryId, c.GetGenderByName)
+}
+
+type OfflineNationalityClient struct {
+	dataset *Dataset
+}
+
+func NewOfflineNationalityClient(dataset *Dataset) *OfflineNationalityClient {
+	return &OfflineNationalityClient{dataset}
+}
+
+func (c *OfflineNationalityClient) GetNationalityByName(ctx context.Context, name string) (*model.NationalityEstimate, error) {
+	nationality := c.dataset.names[datasetKey(name)].nationality
+	if nationality == nil {
+		return nil, nil
+	}
+
+	return model.NewNationalityEstimate(nationality.Countries, nationality.Count), nil
+}
+
+func (c *OfflineNationalityClient) GetNationalitiesByNames(ctx context.Context, names []string) ([]*model.NationalityEstimate, error) {
+	return lookupEach(ctx, names, "", func(ctx context.Context, name string, _ string) (*model.NationalityEstimate, error) {
+		return c.GetNationalityByName(ctx, name)
+	})
+}
+
+// lookupEach answers a batch one name at a time, the dataset is in memory.
+func lookupEach[T any](ctx context.Context, names []string, countryId string, lookup func(context.Context, string, string) (*T, error)) ([]*T, error) {
+	estimates := make([]*T, len(names))
+	for i, name := range names {
+		estimate, err := lookup(ctx, name, countryId)
+		if err != nil {
+			return nil, err
+		}
+		estimates[i] = estimate
+	}
+
+	return estimates, nil
+}
@@ -0,0 +1,110 @@
+package client_registry
+
+import (
+	"context"
+	"errors"
+
+	"github.com/ivanjabrony/personApi/internal/client"
+	"github.com/ivanjabrony/personApi/internal/model"
+)
+
+type ChainAgeClient struct {
+	providers []client.AgeClient
+}
+
+func (c *ChainAgeClient) GetAgeByName(ctx context.Context, name string, countryId string) (*model.AgeEstimate, error) {
+	lookups := make([]func(context.Context) (*model.AgeEstimate, error), len(c.providers))
+	for i, provider := range c.providers {
+		lookups[i] = func(ctx context.Context) (*model.AgeEstimate, error) {
+			return provider.GetAgeByName(ctx, name, countryId)
+		}
+	}
+
+	return firstAnswer(ctx, lookups)
+}
+
+func (c *ChainAgeClient) GetAgesByNames(ctx context.Context, names []string, countryId string) ([]*model.AgeEstimate, error) {
+	lookups := make([]func(context.Context) ([]*model.AgeEstimate, error), len(c.providers))
+	for i, provider := range c.providers {
+		lookups[i] = func(ctx context.Context) ([]*model.AgeEstimate, error) {
+			return provider.GetAgesByNames(ctx, names, countryId)
+		}
+	}
+
+	return firstAnswer(ctx, lookups)
+}
+
+type ChainGenderClient struct {
+	providers []client.GenderClient
+}
+
+func (c *ChainGenderClient) GetGenderByName(ctx context.Context, name string, countryId string) (*model.GenderEstimate, error) {
+	lookups := make([]func(context.Context) (*model.GenderEstimate, error), len(c.providers))
+	for i, provider := range c.providers {
+		lookups[i] = func(ctx context.Context) (*model.GenderEstimate, error) {
+			return provider.GetGenderByName(ctx, name, countryId)
+		}
+	}
+
+	return firstAnswer(ctx, lookups)
+}
+
+func (c *ChainGenderClient) GetGendersByNames(ctx context.Context, names []string, countryId string) ([]*model.GenderEstimate, error) {
+	lookups := make([]func(context.Context) ([]*model.GenderEstimate, error), len(c.providers))
+	for i, provider := range c.providers {
+		lookups[i] = func(ctx context.Context) ([]*model.GenderEstimate, error) {
+			return provider.GetGendersByNames(ctx, names, countryId)
+		}
+	}
+
+	return firstAnswer(ctx, lookups)
+}
+
+type ChainNationalityClient struct {
+	providers []client.NationalityClient
+}
+
+func (c *ChainNationalityClient) GetNationalityByName(ctx context.Context, name string) (*model.NationalityEstimate, error) {
+	lookups := make([]func(context.Context) (*model.NationalityEstimate, error), len(c.providers))
+	for i, provider := range c.providers {
+		lookups[i] = func(ctx context.Context) (*model.NationalityEstimate, error) {
+			return provider.GetNationalityByName(ctx, name)
+		}
+	}
+
+	return firstAnswer(ctx, lookups)
+}
+
+func (c *ChainNationalityClient) GetNationalitiesByNames(ctx context.Context, names []string) ([]*model.NationalityEstimate, error) {
+	lookups := make([]func(context.Context) ([]*model.NationalityEstimate, error), len(c.providers))
+	for i, provider := range c.providers {
+		lookups[i] = func(ctx context.Context) ([]*model.NationalityEstimate, error) {
+			return provider.GetNationalitiesByNames(ctx, names)
+		}
+	}
+
+	return firstAnswer(ctx, lookups)
+}
+
+// firstAnswer returns the answer of the first lookup that does not fail. An
+// unknown name is an answer too, so it does not fall through to the next one.
+// A batch falls through as a whole.
+func firstAnswer[R any](ctx context.Context, lookups []func(context.Context) (R, error)) (R, error) {
+	var (
+		errs error
+		zero R
+	)
+	for _, lookup := range lookups {
+		value, err := lookup(ctx)
+		if err == nil {
+			return value, nil
+		}
+
+		errs = errors.Join(errs, err)
+		if ctx.Err() != nil {
+			break
+		}
+	}
+
+	return zero, errs
+}
@@ -0,0 +1,84 @@
+package client_registry
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/ivanjabrony/personApi/internal/client"
+)
+
+// Registry maps provider names to clients. A provider spec names one provider
+// or a comma separated chain of them, e.g. "http,offline", where the next
+// provider is asked only when the previous one fails.
+type Registry struct {
+	age         map[string]client.AgeClient
+	gender      map[string]client.GenderClient
+	nationality map[string]client.NationalityClient
+}
+
+func NewRegistry() *Registry {
+	return &Registry{
+		age:         make(map[string]client.AgeClient),
+		gender:      make(map[string]client.GenderClient),
+		nationality: make(map[string]client.NationalityClient),
+	}
+}
+
+func (r *Registry) RegisterAge(name string, ageClient client.AgeClient) {
+	r.age[name] = ageClient
+}
+
+func (r *Registry) RegisterGender(name string, genderClient client.GenderClient) {
+	r.gender[name] = genderClient
+}
+
+func (r *Registry) RegisterNationality(name string, nationalityClient client.NationalityClient) {
+	r.nationality[name] = nationalityClient
+}
+
+func (r *Registry) AgeClient(spec string) (client.AgeClient, error) {
+	providers, err := resolve("age", r.age, spec)
+	if err != nil {
+		return nil, err
+	}
+	if len(providers) == 1 {
+		return providers[0], nil
+	}
+	return &ChainAgeClient{providers}, nil
+}
+
+func (r *Registry) GenderClient(spec string) (client.GenderClient, error) {
+	providers, err := resolve("gender", r.gender, spec)
+	if err != nil {
+		return nil, err
+	}
+	if len(providers) == 1 {
+		return providers[0], nil
+	}
+	return &ChainGenderClient{providers}, nil
+}
+
+func (r *Registry) NationalityClient(spec string) (client.NationalityClient, error) {
+	providers, err := resolve("nationality", r.nationality, spec)
+	if err != nil {
+		return nil, err
+	}
+	if len(providers) == 1 {
+		return providers[0], nil
+	}
+	return &ChainNationalityClient{providers}, nil
+}
+
+func resolve[T any](kind string, registered map[string]T, spec string) ([]T, error) {
+	var providers []T
+	for _, name := range strings.Split(spec, ",") {
+		name = strings.TrimSpace(name)
+		provider, ok := registered[name]
+		if !ok {
+			return nil, fmt.Errorf("unknown %s provider %q", kind, name)
+		}
+		providers = append(providers, provider)
+	}
+
+	return providers, nil
+}
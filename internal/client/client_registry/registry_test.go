@@ -0,0 +1,171 @@
+package client_registry
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/ivanjabrony/personApi/internal/client"
+	"github.com/ivanjabrony/personApi/internal/model"
+)
+
+type stubAgeClient struct {
+	age   *model.AgeEstimate
+	err   error
+	calls int
+}
+
+func (c *stubAgeClient) GetAgeByName(context.Context, string, string) (*model.AgeEstimate, error) {
+	c.calls++
+	return c.age, c.err
+}
+
+func (c *stubAgeClient) GetAgesByNames(_ context.Context, names []string, _ string) ([]*model.AgeEstimate, error) {
+	c.calls++
+	if c.err != nil {
+		return nil, c.err
+	}
+
+	ages := make([]*model.AgeEstimate, len(names))
+	for i := range ages {
+		ages[i] = c.age
+	}
+	return ages, nil
+}
+
+func TestAgeChain(t *testing.T) {
+	errBroken := errors.New("broken")
+
+	tests := []struct {
+		name      string
+		providers []*stubAgeClient
+		wantAge   *int
+		wantErrs  []error
+		wantCalls []int
+	}{
+		{
+			name:      "first answer wins",
+			providers: []*stubAgeClient{{age: &model.AgeEstimate{Age: 30}}, {age: &model.AgeEstimate{Age: 40}}},
+			wantAge:   ptr(30),
+			wantCalls: []int{1, 0},
+		},
+		{
+			name:      "unavailable provider falls through",
+			providers: []*stubAgeClient{{err: model.ErrUpstreamUnavailable}, {age: &model.AgeEstimate{Age: 40}}, {age: &model.AgeEstimate{Age: 50}}},
+			wantAge:   ptr(40),
+			wantCalls: []int{1, 1, 0},
+		},
+		{
+			name:      "failing provider falls through",
+			providers: []*stubAgeClient{{err: errBroken}, {age: &model.AgeEstimate{Age: 40}}},
+			wantAge:   ptr(40),
+			wantCalls: []int{1, 1},
+		},
+		{
+			name:      "unknown name is an answer",
+			providers: []*stubAgeClient{{}, {age: &model.AgeEstimate{Age: 40}}},
+			wantCalls: []int{1, 0},
+		},
+		{
+			name:      "every provider fails",
+			providers: []*stubAgeClient{{err: model.ErrUpstreamUnavailable}, {err: errBroken}},
+			wantErrs:  []error{model.ErrUpstreamUnavailable, errBroken},
+			wantCalls: []int{1, 1},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			registry := NewRegistry()
+			names := make([]string, len(tt.providers))
+			for i, provider := range tt.providers {
+				names[i] = fmt.Sprintf("provider%d", i)
+				registry.RegisterAge(names[i], provider)
+			}
+			spec := strings.Join(names, ",")
+
+			ageClient, err := registry.AgeClient(spec)
+			if err != nil {
+				t.Fatalf("AgeClient(%q) error = %v", spec, err)
+			}
+
+			age, err := ageClient.GetAgeByName(context.Background(), "Ann", "")
+			for _, want := range tt.wantErrs {
+				if !errors.Is(err, want) {
+					t.Errorf("GetAgeByName() error = %v, want %v", err, want)
+				}
+			}
+			if len(tt.wantErrs) == 0 && err != nil {
+				t.Fatalf("GetAgeByName() error = %v", err)
+			}
+
+			switch {
+			case tt.wantAge == nil && age != nil:
+				t.Errorf("age = %+v, want nil", age)
+			case tt.wantAge != nil && (age == nil || age.Age != *tt.wantAge):
+				t.Errorf("age = %+v, want %d", age, *tt.wantAge)
+			}
+
+			for i, provider := range tt.providers {
+				if provider.calls != tt.wantCalls[i] {
+					t.Errorf("provider %d called %d times, want %d", i, provider.calls, tt.wantCalls[i])
+				}
+			}
+		})
+	}
+}
+
+func TestAgeChainBatchFallsThroughAsWhole(t *testing.T) {
+	first := &stubAgeClient{err: model.ErrUpstreamUnavailable}
+	second := &stubAgeClient{age: &model.AgeEstimate{Age: 40}}
+	chain := &ChainAgeClient{providers: []client.AgeClient{first, second}}
+
+	ages, err := chain.GetAgesByNames(context.Background(), []string{"Ann", "Bob"}, "")
+	if err != nil {
+		t.Fatalf("GetAgesByNames() error = %v", err)
+	}
+	if len(ages) != 2 || ages[0].Age != 40 || ages[1].Age != 40 {
+		t.Errorf("ages = %+v, want both from the second provider", ages)
+	}
+}
+
+func TestChainStopsWhenContextIsDone(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	first := &stubAgeClient{err: context.Canceled}
+	second := &stubAgeClient{age: &model.AgeEstimate{Age: 40}}
+	chain := &ChainAgeClient{providers: []client.AgeClient{first, second}}
+
+	_, err := chain.GetAgeByName(ctx, "Ann", "")
+	if !errors.Is(err, context.Canceled) {
+		t.Fatalf("GetAgeByName() error = %v, want %v", err, context.Canceled)
+	}
+	if second.calls != 0 {
+		t.Errorf("second provider called %d times after the context was done", second.calls)
+	}
+}
+
+func TestRegistryResolve(t *testing.T) {
+	registry := NewRegistry()
+	only := &stubAgeClient{}
+	registry.RegisterAge("http", only)
+
+	ageClient, err := registry.AgeClient("http")
+	if err != nil {
+		t.Fatalf("AgeClient() error = %v", err)
+	}
+	if ageClient != only {
+		t.Errorf("AgeClient() = %T, want the provider itself", ageClient)
+	}
+
+	if _, err := registry.AgeClient("http, offline"); err == nil {
+		t.Error("AgeClient() with an unknown provider succeeded")
+	}
+}
+
+func ptr[T any](value T) *T {
+	return &value
+}
@@ -0,0 +1,84 @@
+package client_transport
+
+import (
+	"sync"
+	"time"
+
+	"github.com/ivanjabrony/personApi/internal/model"
+)
+
+// circuitBreaker stops calls to an upstream after threshold consecutive
+// failures. Once cooldown has passed a single probe is let through, its
+// outcome closes the breaker or opens it for another cooldown.
+type circuitBreaker struct {
+	mu        sync.Mutex
+	threshold int
+	cooldown  time.Duration
+	state     string
+	failures  int
+	openUntil time.Time
+	probing   bool
+}
+
+func newCircuitBreaker(threshold int, cooldown time.Duration) *circuitBreaker {
+	return &circuitBreaker{threshold: threshold, cooldown: cooldown, state: model.BreakerClosed}
+}
+
+func (b *circuitBreaker) allow() bool {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	switch b.state {
+	case model.BreakerOpen:
+		if time.Now().Before(b.openUntil) {
+			return false
+		}
+		b.state = model.BreakerHalfOpen
+		b.probing = true
+		return true
+	case model.BreakerHalfOpen:
+		if b.probing {
+			return false
+		}
+		b.probing = true
+		return true
+	default:
+		return true
+	}
+}
+
+func (b *circuitBreaker) record(success bool) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.probing = false
+	if success {
+		b.state = model.BreakerClosed
+		b.failures = 0
+		return
+	}
+
+	b.failures++
+	if b.state == model.BreakerHalfOpen || (b.threshold > 0 && b.failures >= b.threshold) {
+		b.state = model.BreakerOpen
+		b.openUntil = time.Now().Add(b.cooldown)
+	}
+}
+
+// release gives up a call without judging the upstream.
+func (b *circuitBreaker) release() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.probing = false
+}
+
+func (b *circuitBreaker) currentState() string {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if b.state == model.BreakerOpen && !time.Now().Before(b.openUntil) {
+		return model.BreakerHalfOpen
+	}
+	return b.state
+}
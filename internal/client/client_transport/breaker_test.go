@@ -0,0 +1,142 @@
+package client_transport
+
+import (
+	"testing"
+	"time"
+
+	"github.com/ivanjabrony/personApi/internal/model"
+)
+
+func TestCircuitBreaker(t *testing.T) {
+	type step struct {
+		action string // allow, success, failure or release
+		want   bool   // result of allow
+		state  string // state after the step
+	}
+
+	tests := []struct {
+		name      string
+		threshold int
+		cooldown  time.Duration
+		steps     []step
+	}{
+		{
+			name:      "stays closed below the threshold",
+			threshold: 3,
+			cooldown:  time.Hour,
+			steps: []step{
+				{action: "failure", state: model.BreakerClosed},
+				{action: "failure", state: model.BreakerClosed},
+				{action: "allow", want: true, state: model.BreakerClosed},
+			},
+		},
+		{
+			name:      "success resets the failure count",
+			threshold: 2,
+			cooldown:  time.Hour,
+			steps: []step{
+				{action: "failure", state: model.BreakerClosed},
+				{action: "success", state: model.BreakerClosed},
+				{action: "failure", state: model.BreakerClosed},
+				{action: "allow", want: true, state: model.BreakerClosed},
+			},
+		},
+		{
+			name:      "opens at the threshold and rejects during cooldown",
+			threshold: 2,
+			cooldown:  time.Hour,
+			steps: []step{
+				{action: "failure", state: model.BreakerClosed},
+				{action: "failure", state: model.BreakerOpen},
+				{action: "allow", want: false, state: model.BreakerOpen},
+			},
+		},
+		{
+			name:      "lets a single probe through after cooldown",
+			threshold: 1,
+			cooldown:  0,
+			steps: []step{
+				{action: "failure", state: model.BreakerHalfOpen},
+				{action: "allow", want: true, state: model.BreakerHalfOpen},
+				{action: "allow", want: false, state: model.BreakerHalfOpen},
+			},
+		},
+		{
+			name:      "successful probe closes",
+			threshold: 1,
+			cooldown:  0,
+			steps: []step{
+				{action: "failure"},
+				{action: "allow", want: true},
+				{action: "success", state: model.BreakerClosed},
+				{action: "allow", want: true, state: model.BreakerClosed},
+			},
+		},
+		{
+			name:      "released probe lets the next one through",
+			threshold: 1,
+			cooldown:  0,
+			steps: []step{
+				{action: "failure"},
+				{action: "allow", want: true},
+				{action: "release", state: model.BreakerHalfOpen},
+				{action: "allow", want: true, state: model.BreakerHalfOpen},
+			},
+		},
+		{
+			name:      "zero threshold never opens",
+			threshold: 0,
+			cooldown:  time.Hour,
+			steps: []step{
+				{action: "failure"}, {action: "failure"}, {action: "failure"},
+				{action: "allow", want: true, state: model.BreakerClosed},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			breaker := newCircuitBreaker(tt.threshold, tt.cooldown)
+
+			for i, step := range tt.steps {
+				switch step.action {
+				case "allow":
+					if got := breaker.allow(); got != step.want {
+						t.Fatalf("step %d: allow() = %t, want %t", i, got, step.want)
+					}
+				case "success":
+					breaker.record(true)
+				case "failure":
+					breaker.record(false)
+				case "release":
+					breaker.release()
+				}
+
+				if step.state != "" {
+					if got := breaker.currentState(); got != step.state {
+						t.Fatalf("step %d: state = %s, want %s", i, got, step.state)
+					}
+				}
+			}
+		})
+	}
+}
+
+func TestCircuitBreakerFailedProbeReopens(t *testing.T) {
+	breaker := newCircuitBreaker(1, time.Hour)
+	breaker.record(false)
+
+	// pretend the cooldown is over
+	breaker.openUntil = time.Now().Add(-time.Second)
+	if !breaker.allow() {
+		t.Fatal("allow() = false after cooldown, want a probe")
+	}
+
+	breaker.record(false)
+	if got := breaker.currentState(); got != model.BreakerOpen {
+		t.Fatalf("state = %s, want %s", got, model.BreakerOpen)
+	}
+	if breaker.allow() {
+		t.Fatal("allow() = true right after a failed probe")
+	}
+}
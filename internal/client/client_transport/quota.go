@@ -0,0 +1,74 @@
+package client_transport
+
+import (
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+)
+
+// quota tracks what an upstream reports in its X-Rate-Limit-* headers. Once
+// the remaining quota hits zero no call is made until the reported reset.
+type quota struct {
+	mu        sync.Mutex
+	known     bool
+	limit     int
+	remaining int
+	resetAt   time.Time
+}
+
+func (q *quota) exhaustedUntil() (time.Time, bool) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	if q.known && q.remaining <= 0 && time.Now().Before(q.resetAt) {
+		return q.resetAt, true
+	}
+	return time.Time{}, false
+}
+
+// update reads the quota headers, X-Rate-Limit-Reset is the amount of
+// seconds until the quota is renewed. A 429 without headers exhausts the
+// quota until Retry-After, when it is given.
+func (q *quota) update(resp *http.Response) {
+	remaining, remainingErr := strconv.Atoi(resp.Header.Get("X-Rate-Limit-Remaining"))
+	reset, resetErr := strconv.Atoi(resp.Header.Get("X-Rate-Limit-Reset"))
+	limit, limitErr := strconv.Atoi(resp.Header.Get("X-Rate-Limit-Limit"))
+
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	if remainingErr == nil {
+		q.known = true
+		q.remaining = remaining
+	}
+	if resetErr == nil {
+		q.resetAt = time.Now().Add(time.Duration(reset) * time.Second)
+	}
+	if limitErr == nil {
+		q.limit = limit
+	}
+
+	if resp.StatusCode == http.StatusTooManyRequests && remainingErr != nil {
+		if delay, ok := parseRetryAfter(resp.Header.Get("Retry-After")); ok {
+			q.known = true
+			q.remaining = 0
+			q.resetAt = time.Now().Add(delay)
+		}
+	}
+}
+
+func (q *quota) snapshot() (limit *int, remaining *int, resetAt *time.Time) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	if !q.known {
+		return nil, nil, nil
+	}
+
+	l, r, at := q.limit, q.remaining, q.resetAt
+	if l > 0 {
+		limit = &l
+	}
+	return limit, &r, &at
+}
@@ -0,0 +1,191 @@
+package client_transport
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/ivanjabrony/personApi/internal/model"
+)
+
+func TestQuotaUpdate(t *testing.T) {
+	tests := []struct {
+		name          string
+		status        int
+		headers       map[string]string
+		wantKnown     bool
+		wantRemaining int
+		wantLimit     *int
+		wantExhausted bool
+	}{
+		{name: "missing headers", status: 200},
+		{
+			name:    "malformed remaining",
+			status:  200,
+			headers: map[string]string{"X-Rate-Limit-Remaining": "plenty", "X-Rate-Limit-Reset": "60"},
+		},
+		{
+			name:          "quota left",
+			status:        200,
+			headers:       map[string]string{"X-Rate-Limit-Remaining": "5", "X-Rate-Limit-Reset": "60", "X-Rate-Limit-Limit": "100"},
+			wantKnown:     true,
+			wantRemaining: 5,
+			wantLimit:     ptr(100),
+		},
+		{
+			name:          "malformed limit is left out",
+			status:        200,
+			headers:       map[string]string{"X-Rate-Limit-Remaining": "5", "X-Rate-Limit-Limit": "many"},
+			wantKnown:     true,
+			wantRemaining: 5,
+		},
+		{
+			name:          "zero remaining until the reset",
+			status:        200,
+			headers:       map[string]string{"X-Rate-Limit-Remaining": "0", "X-Rate-Limit-Reset": "60"},
+			wantKnown:     true,
+			wantExhausted: true,
+		},
+		{
+			name:      "zero remaining with a past reset",
+			status:    200,
+			headers:   map[string]string{"X-Rate-Limit-Remaining": "0", "X-Rate-Limit-Reset": "0"},
+			wantKnown: true,
+		},
+		{
+			name:      "zero remaining without a reset",
+			status:    200,
+			headers:   map[string]string{"X-Rate-Limit-Remaining": "0"},
+			wantKnown: true,
+		},
+		{
+			name:          "429 without quota headers waits for Retry-After",
+			status:        http.StatusTooManyRequests,
+			headers:       map[string]string{"Retry-After": "30"},
+			wantKnown:     true,
+			wantExhausted: true,
+		},
+		{
+			name:   "429 without any hint",
+			status: http.StatusTooManyRequests,
+		},
+		{
+			name:          "429 with quota headers trusts them",
+			status:        http.StatusTooManyRequests,
+			headers:       map[string]string{"X-Rate-Limit-Remaining": "3", "Retry-After": "30"},
+			wantKnown:     true,
+			wantRemaining: 3,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var q quota
+			q.update(quotaResponse(tt.status, tt.headers))
+
+			limit, remaining, _ := q.snapshot()
+			if (remaining != nil) != tt.wantKnown {
+				t.Fatalf("snapshot() remaining = %v, want known %t", remaining, tt.wantKnown)
+			}
+			if remaining != nil && *remaining != tt.wantRemaining {
+				t.Errorf("remaining = %d, want %d", *remaining, tt.wantRemaining)
+			}
+			if (limit == nil) != (tt.wantLimit == nil) || (limit != nil && *limit != *tt.wantLimit) {
+				t.Errorf("limit = %v, want %v", limit, tt.wantLimit)
+			}
+
+			if _, exhausted := q.exhaustedUntil(); exhausted != tt.wantExhausted {
+				t.Errorf("exhaustedUntil() = %t, want %t", exhausted, tt.wantExhausted)
+			}
+		})
+	}
+}
+
+// quotaRoundTripper answers every call with the same status and headers.
+type quotaRoundTripper struct {
+	status  int
+	headers map[string]string
+	calls   int
+}
+
+func (q *quotaRoundTripper) RoundTrip(req *http.Request) (*http.Response, error) {
+	q.calls++
+	resp := quotaResponse(q.status, q.headers)
+	resp.Request = req
+	return resp, nil
+}
+
+func TestRoundTripQuotaAndTokenBucket(t *testing.T) {
+	// one token that takes an hour to come back
+	config := Config{RateLimit: 1.0 / 3600, RateBurst: 1, BreakerThreshold: 10, BreakerCooldown: time.Hour}
+
+	tests := []struct {
+		name    string
+		headers map[string]string
+		// wantRateLimited tells the second call waited for the bucket
+		// rather than failing on the quota first.
+		wantRateLimited bool
+	}{
+		{
+			name:    "exhausted quota fails without waiting for the bucket",
+			headers: map[string]string{"X-Rate-Limit-Remaining": "0", "X-Rate-Limit-Reset": "60"},
+		},
+		{
+			name:            "quota left still waits for the bucket",
+			headers:         map[string]string{"X-Rate-Limit-Remaining": "10", "X-Rate-Limit-Reset": "60"},
+			wantRateLimited: true,
+		},
+		{
+			name:            "missing quota headers leave the bucket in charge",
+			wantRateLimited: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			next := &quotaRoundTripper{status: 200, headers: tt.headers}
+			transport := newTestTransport(config, next)
+
+			req, _ := http.NewRequest(http.MethodGet, "https://api.agify.io/?name=anna", nil)
+			if _, err := transport.RoundTrip(req); err != nil {
+				t.Fatalf("first RoundTrip() error = %v", err)
+			}
+
+			ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+			defer cancel()
+
+			req, _ = http.NewRequestWithContext(ctx, http.MethodGet, "https://api.agify.io/?name=anna", nil)
+			_, err := transport.RoundTrip(req)
+			if !errors.Is(err, model.ErrUpstreamUnavailable) {
+				t.Fatalf("second RoundTrip() error = %v, want %v", err, model.ErrUpstreamUnavailable)
+			}
+			if rateLimited := strings.Contains(err.Error(), "rate limit"); rateLimited != tt.wantRateLimited {
+				t.Errorf("second RoundTrip() error = %v, want rate limited %t", err, tt.wantRateLimited)
+			}
+			if next.calls != 1 {
+				t.Errorf("calls = %d, want 1", next.calls)
+			}
+		})
+	}
+}
+
+func quotaResponse(status int, headers map[string]string) *http.Response {
+	resp := &http.Response{
+		StatusCode: status,
+		Header:     http.Header{},
+		Body:       io.NopCloser(strings.NewReader("")),
+	}
+	for key, value := range headers {
+		resp.Header.Set(key, value)
+	}
+
+	return resp
+}
+
+func ptr[T any](value T) *T {
+	return &value
+}
@@ -0,0 +1,21 @@
+package client_transport
+
+import (
+	"net/http"
+
+	"github.com/ivanjabrony/personApi/internal/tracing"
+	"go.opentelemetry.io/contrib/instrumentation/net/http/otelhttp"
+)
+
+// traced records a client span for every attempt made within a trace and
+// passes its traceparent on to the upstream.
+func traced(next http.RoundTripper) http.RoundTripper {
+	return otelhttp.NewTransport(next,
+		otelhttp.WithFilter(func(req *http.Request) bool {
+			return tracing.InTrace(req.Context())
+		}),
+		otelhttp.WithSpanNameFormatter(func(_ string, req *http.Request) string {
+			return req.Method + " " + req.URL.Host
+		}),
+	)
+}
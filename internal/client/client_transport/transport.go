@@ -0,0 +1,297 @@
+package client_transport
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"math/rand/v2"
+	"net"
+	"net/http"
+	"net/url"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/ivanjabrony/personApi/internal/model"
+	"golang.org/x/time/rate"
+)
+
+type Config struct {
+	// Timeout bounds a whole call including retries, AttemptTimeout bounds
+	// connecting and waiting for the response headers of one attempt.
+	Timeout        time.Duration
+	AttemptTimeout time.Duration
+
+	MaxRetries      int
+	RetryBackoff    time.Duration
+	MaxRetryBackoff time.Duration
+
+	BreakerThreshold int
+	BreakerCooldown  time.Duration
+
+	MaxIdleConnsPerHost int
+
+	// RateLimit is the amount of calls per second allowed to one upstream,
+	// zero disables the limit.
+	RateLimit float64
+	RateBurst int
+}
+
+// Transport guards every upstream host with a token bucket, its reported
+// quota and a circuit breaker, and retries idempotent requests that failed
+// with a network error, 429 or 5xx.
+type Transport struct {
+	next     http.RoundTripper
+	config   Config
+	observer CallObserver
+
+	mu        sync.Mutex
+	upstreams map[string]*upstream
+}
+
+type upstream struct {
+	baseURL string
+	breaker *circuitBreaker
+	limiter *rate.Limiter
+	quota   quota
+}
+
+// CallObserver is told about every attempt to call an upstream, including
+// the ones the transport rejects itself.
+type CallObserver interface {
+	ObserveUpstreamCall(upstream string, outcome string, duration time.Duration)
+}
+
+func NewTransport(config Config, observer CallObserver) *Transport {
+	dialer := &net.Dialer{Timeout: config.AttemptTimeout, KeepAlive: 30 * time.Second}
+
+	return &Transport{
+		next: traced(&http.Transport{
+			Proxy:                 http.ProxyFromEnvironment,
+			DialContext:           dialer.DialContext,
+			TLSHandshakeTimeout:   config.AttemptTimeout,
+			ResponseHeaderTimeout: config.AttemptTimeout,
+			MaxIdleConns:          config.MaxIdleConnsPerHost * 4,
+			MaxIdleConnsPerHost:   config.MaxIdleConnsPerHost,
+			IdleConnTimeout:       90 * time.Second,
+			ForceAttemptHTTP2:     true,
+		}),
+		config:    config,
+		observer:  observer,
+		upstreams: make(map[string]*upstream),
+	}
+}
+
+// NewHTTPClient returns a client to share between all upstream clients.
+func NewHTTPClient(transport *Transport) *http.Client {
+	return &http.Client{Transport: transport, Timeout: transport.config.Timeout}
+}
+
+func (t *Transport) RoundTrip(req *http.Request) (*http.Response, error) {
+	upstream := t.upstream(req.URL.Host)
+	breaker := upstream.breaker
+	idempotent := (req.Method == http.MethodGet || req.Method == http.MethodHead) && req.Body == nil
+
+	for attempt := 0; ; attempt++ {
+		if resetAt, exhausted := upstream.quota.exhaustedUntil(); exhausted {
+			t.observer.ObserveUpstreamCall(req.URL.Host, "quota_exhausted", 0)
+			return nil, fmt.Errorf("%w: quota of %s is exhausted until %s", model.ErrUpstreamUnavailable, req.URL.Host, resetAt.Format(time.RFC3339))
+		}
+
+		if err := upstream.limiter.Wait(req.Context()); err != nil {
+			t.observer.ObserveUpstreamCall(req.URL.Host, "rate_limited", 0)
+			return nil, fmt.Errorf("%w: rate limit of %s: %w", model.ErrUpstreamUnavailable, req.URL.Host, err)
+		}
+
+		if !breaker.allow() {
+			t.observer.ObserveUpstreamCall(req.URL.Host, "breaker_open", 0)
+			return nil, fmt.Errorf("%w: circuit breaker for %s is open", model.ErrUpstreamUnavailable, req.URL.Host)
+		}
+
+		start := time.Now()
+		resp, err := t.next.RoundTrip(req)
+		t.observer.ObserveUpstreamCall(req.URL.Host, callOutcome(resp, err), time.Since(start))
+
+		failed := isFailure(resp, err)
+		if req.Context().Err() == nil {
+			breaker.record(!failed)
+		} else {
+			// a caller giving up says nothing about the upstream
+			breaker.release()
+		}
+
+		if resp != nil {
+			upstream.quota.update(resp)
+		}
+
+		if !failed || !idempotent || attempt >= t.config.MaxRetries || req.Context().Err() != nil {
+			return resp, err
+		}
+
+		delay, ok := t.retryDelay(attempt, resp)
+		if deadline, hasDeadline := req.Context().Deadline(); !ok || (hasDeadline && time.Until(deadline) < delay) {
+			return resp, err
+		}
+
+		if resp != nil {
+			_, _ = io.Copy(io.Discard, io.LimitReader(resp.Body, 4096))
+			resp.Body.Close()
+		}
+
+		timer := time.NewTimer(delay)
+		select {
+		case <-req.Context().Done():
+			timer.Stop()
+			return nil, req.Context().Err()
+		case <-timer.C:
+		}
+	}
+}
+
+// Track makes an upstream known before its first call, so it is reported
+// by UpstreamStatuses from the start.
+func (t *Transport) Track(baseURL string) {
+	if parsed, err := url.Parse(baseURL); err == nil {
+		u := t.upstream(parsed.Host)
+
+		t.mu.Lock()
+		u.baseURL = baseURL
+		t.mu.Unlock()
+	}
+}
+
+// Probe checks that host answers at all. The probe skips retries, the rate
+// limit and the breaker, so health checks don't change how calls are guarded.
+func (t *Transport) Probe(ctx context.Context, host string) error {
+	t.mu.Lock()
+	probeURL := "https://" + host + "/"
+	if u, ok := t.upstreams[host]; ok && u.baseURL != "" {
+		probeURL = u.baseURL
+	}
+	t.mu.Unlock()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodHead, probeURL, nil)
+	if err != nil {
+		return fmt.Errorf("failed to create probe of %s: %w", host, err)
+	}
+
+	resp, err := t.next.RoundTrip(req)
+	if err != nil {
+		return fmt.Errorf("%w: probe of %s failed: %w", model.ErrUpstreamUnavailable, host, err)
+	}
+	resp.Body.Close()
+
+	if resp.StatusCode >= http.StatusInternalServerError {
+		return fmt.Errorf("%w: %s returned status: %d", model.ErrUpstreamUnavailable, host, resp.StatusCode)
+	}
+
+	return nil
+}
+
+// UpstreamStatuses reports the state of every known upstream ordered by host.
+func (t *Transport) UpstreamStatuses() []model.UpstreamStatus {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	statuses := make([]model.UpstreamStatus, 0, len(t.upstreams))
+	for host, upstream := range t.upstreams {
+		status := model.UpstreamStatus{
+			Host:    host,
+			Breaker: upstream.breaker.currentState(),
+		}
+		if limit := upstream.limiter.Limit(); limit != rate.Inf {
+			perSecond := float64(limit)
+			status.RateLimit = &perSecond
+		}
+		status.QuotaLimit, status.QuotaRemaining, status.QuotaResetAt = upstream.quota.snapshot()
+		_, status.QuotaExhausted = upstream.quota.exhaustedUntil()
+
+		statuses = append(statuses, status)
+	}
+
+	slices.SortFunc(statuses, func(a, b model.UpstreamStatus) int {
+		return strings.Compare(a.Host, b.Host)
+	})
+
+	return statuses
+}
+
+func (t *Transport) upstream(host string) *upstream {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	u, ok := t.upstreams[host]
+	if !ok {
+		limit := rate.Inf
+		if t.config.RateLimit > 0 {
+			limit = rate.Limit(t.config.RateLimit)
+		}
+
+		u = &upstream{
+			breaker: newCircuitBreaker(t.config.BreakerThreshold, t.config.BreakerCooldown),
+			limiter: rate.NewLimiter(limit, max(t.config.RateBurst, 1)),
+		}
+		t.upstreams[host] = u
+	}
+
+	return u
+}
+
+// retryDelay honours Retry-After and otherwise backs off exponentially with
+// full jitter. It reports false when the upstream asks to wait longer than
+// MaxRetryBackoff, a retry would not fit into the call anyway.
+func (t *Transport) retryDelay(attempt int, resp *http.Response) (time.Duration, bool) {
+	if resp != nil {
+		if delay, ok := parseRetryAfter(resp.Header.Get("Retry-After")); ok {
+			return delay, delay <= t.config.MaxRetryBackoff
+		}
+	}
+
+	delay := t.config.RetryBackoff
+	for i := 0; i < attempt && delay < t.config.MaxRetryBackoff; i++ {
+		delay *= 2
+	}
+	delay = min(delay, t.config.MaxRetryBackoff)
+
+	if delay <= 0 {
+		return 0, true
+	}
+	return rand.N(delay + 1), true
+}
+
+func isFailure(resp *http.Response, err error) bool {
+	if err != nil {
+		return true
+	}
+	return resp.StatusCode == http.StatusTooManyRequests || resp.StatusCode >= http.StatusInternalServerError
+}
+
+// callOutcome sorts an attempt into a bounded set of label values.
+func callOutcome(resp *http.Response, err error) string {
+	switch {
+	case err != nil:
+		return "error"
+	case resp.StatusCode == http.StatusTooManyRequests:
+		return "429"
+	default:
+		return strconv.Itoa(resp.StatusCode/100) + "xx"
+	}
+}
+
+func parseRetryAfter(value string) (time.Duration, bool) {
+	if value == "" {
+		return 0, false
+	}
+
+	if seconds, err := strconv.Atoi(value); err == nil && seconds >= 0 {
+		return time.Duration(seconds) * time.Second, true
+	}
+
+	if at, err := http.ParseTime(value); err == nil {
+		return max(time.Until(at), 0), true
+	}
+
+	return 0, false
+}
@@ -0,0 +1,185 @@
+package client_transport
+
+import (
+	"errors"
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/ivanjabrony/personApi/internal/model"
+)
+
+type nopObserver struct{}
+
+func (nopObserver) ObserveUpstreamCall(string, string, time.Duration) {}
+
+// scriptedRoundTripper answers with the given statuses in turn, 0 stands for
+// a network error.
+type scriptedRoundTripper struct {
+	statuses []int
+	calls    int
+}
+
+func (s *scriptedRoundTripper) RoundTrip(req *http.Request) (*http.Response, error) {
+	status := s.statuses[min(s.calls, len(s.statuses)-1)]
+	s.calls++
+
+	if status == 0 {
+		return nil, errors.New("connection refused")
+	}
+	return &http.Response{
+		StatusCode: status,
+		Header:     http.Header{},
+		Body:       io.NopCloser(strings.NewReader("")),
+		Request:    req,
+	}, nil
+}
+
+func newTestTransport(config Config, next http.RoundTripper) *Transport {
+	transport := NewTransport(config, nopObserver{})
+	transport.next = next
+	return transport
+}
+
+func TestRoundTripRetries(t *testing.T) {
+	config := Config{
+		MaxRetries:       2,
+		RetryBackoff:     time.Millisecond,
+		MaxRetryBackoff:  time.Millisecond,
+		BreakerThreshold: 10,
+		BreakerCooldown:  time.Hour,
+	}
+
+	tests := []struct {
+		name       string
+		method     string
+		body       io.Reader
+		statuses   []int
+		wantCalls  int
+		wantStatus int
+		wantErr    bool
+	}{
+		{name: "success", method: http.MethodGet, statuses: []int{200}, wantCalls: 1, wantStatus: 200},
+		{name: "retries 5xx", method: http.MethodGet, statuses: []int{503, 502, 200}, wantCalls: 3, wantStatus: 200},
+		{name: "retries 429", method: http.MethodGet, statuses: []int{429, 200}, wantCalls: 2, wantStatus: 200},
+		{name: "retries network errors", method: http.MethodGet, statuses: []int{0, 200}, wantCalls: 2, wantStatus: 200},
+		{name: "gives up after MaxRetries", method: http.MethodGet, statuses: []int{500}, wantCalls: 3, wantStatus: 500},
+		{name: "gives up after MaxRetries on errors", method: http.MethodGet, statuses: []int{0}, wantCalls: 3, wantErr: true},
+		{name: "does not retry 4xx", method: http.MethodGet, statuses: []int{404}, wantCalls: 1, wantStatus: 404},
+		{name: "does not retry POST", method: http.MethodPost, body: strings.NewReader("{}"), statuses: []int{503, 200}, wantCalls: 1, wantStatus: 503},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			next := &scriptedRoundTripper{statuses: tt.statuses}
+			transport := newTestTransport(config, next)
+
+			req, _ := http.NewRequest(tt.method, "https://api.agify.io/?name=anna", tt.body)
+			resp, err := transport.RoundTrip(req)
+
+			if next.calls != tt.wantCalls {
+				t.Errorf("calls = %d, want %d", next.calls, tt.wantCalls)
+			}
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("RoundTrip() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if err == nil && resp.StatusCode != tt.wantStatus {
+				t.Errorf("status = %d, want %d", resp.StatusCode, tt.wantStatus)
+			}
+		})
+	}
+}
+
+func TestRoundTripOpenBreakerSkipsUpstream(t *testing.T) {
+	next := &scriptedRoundTripper{statuses: []int{503}}
+	transport := newTestTransport(Config{BreakerThreshold: 2, BreakerCooldown: time.Hour}, next)
+
+	for range 2 {
+		req, _ := http.NewRequest(http.MethodGet, "https://api.agify.io/?name=anna", nil)
+		if _, err := transport.RoundTrip(req); err != nil {
+			t.Fatalf("RoundTrip() error = %v", err)
+		}
+	}
+
+	req, _ := http.NewRequest(http.MethodGet, "https://api.agify.io/?name=anna", nil)
+	_, err := transport.RoundTrip(req)
+	if !errors.Is(err, model.ErrUpstreamUnavailable) {
+		t.Fatalf("RoundTrip() error = %v, want %v", err, model.ErrUpstreamUnavailable)
+	}
+	if next.calls != 2 {
+		t.Errorf("calls = %d, want 2", next.calls)
+	}
+
+	// other hosts are guarded separately
+	req, _ = http.NewRequest(http.MethodGet, "https://api.genderize.io/?name=anna", nil)
+	if _, err := transport.RoundTrip(req); err != nil {
+		t.Fatalf("RoundTrip() to another host error = %v", err)
+	}
+}
+
+func TestRetryDelay(t *testing.T) {
+	config := Config{RetryBackoff: 100 * time.Millisecond, MaxRetryBackoff: time.Second}
+
+	tests := []struct {
+		name       string
+		attempt    int
+		retryAfter string
+		wantMax    time.Duration
+		wantExact  bool
+		wantOk     bool
+	}{
+		{name: "first attempt", attempt: 0, wantMax: 100 * time.Millisecond, wantOk: true},
+		{name: "doubles per attempt", attempt: 2, wantMax: 400 * time.Millisecond, wantOk: true},
+		{name: "capped by MaxRetryBackoff", attempt: 10, wantMax: time.Second, wantOk: true},
+		{name: "Retry-After in seconds", retryAfter: "1", wantMax: time.Second, wantExact: true, wantOk: true},
+		{name: "Retry-After beyond MaxRetryBackoff", retryAfter: "30", wantMax: 30 * time.Second, wantExact: true, wantOk: false},
+		{name: "malformed Retry-After falls back to backoff", retryAfter: "soon", wantMax: 100 * time.Millisecond, wantOk: true},
+	}
+
+	transport := NewTransport(config, nopObserver{})
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			resp := &http.Response{Header: http.Header{}}
+			if tt.retryAfter != "" {
+				resp.Header.Set("Retry-After", tt.retryAfter)
+			}
+
+			// the jitter makes single draws meaningless, check the bounds of many
+			for range 50 {
+				delay, ok := transport.retryDelay(tt.attempt, resp)
+				if ok != tt.wantOk {
+					t.Fatalf("retryDelay() ok = %t, want %t", ok, tt.wantOk)
+				}
+				if delay < 0 || delay > tt.wantMax || (tt.wantExact && delay != tt.wantMax) {
+					t.Fatalf("retryDelay() = %s, want at most %s (exact %t)", delay, tt.wantMax, tt.wantExact)
+				}
+			}
+		})
+	}
+}
+
+func TestParseRetryAfter(t *testing.T) {
+	tests := []struct {
+		value  string
+		want   time.Duration
+		wantOk bool
+	}{
+		{value: "", wantOk: false},
+		{value: "0", want: 0, wantOk: true},
+		{value: "5", want: 5 * time.Second, wantOk: true},
+		{value: "-1", wantOk: false},
+		{value: "soon", wantOk: false},
+		{value: "Mon, 02 Jan 2006 15:04:05 GMT", want: 0, wantOk: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.value, func(t *testing.T) {
+			got, ok := parseRetryAfter(tt.value)
+			if ok != tt.wantOk || got != tt.want {
+				t.Errorf("parseRetryAfter(%q) = %s, %t, want %s, %t", tt.value, got, ok, tt.want, tt.wantOk)
+			}
+		})
+	}
+}
@@ -1,7 +1,14 @@
 package client
 
-import "context"
+import (
+	"context"
 
+	"github.com/ivanjabrony/personApi/internal/model"
+)
+
+// GenderClient estimates the gender of a name, localized to countryId unless it is empty.
 type GenderClient interface {
-	GetGenderByName(ctx context.Context, name string) (*string, error)
+	GetGenderByName(ctx context.Context, name string, countryId string) (*model.GenderEstimate, error)
+	// GetGendersByNames answers in the order of names, nil for an unknown one.
+	GetGendersByNames(ctx context.Context, names []string, countryId string) ([]*model.GenderEstimate, error)
 }
@@ -1,7 +1,13 @@
 package client
 
-import "context"
+import (
+	"context"
+
+	"github.com/ivanjabrony/personApi/internal/model"
+)
 
 type NationalityClient interface {
-	GetNationalityByName(ctx context.Context, name string) (*string, error)
+	GetNationalityByName(ctx context.Context, name string) (*model.NationalityEstimate, error)
+	// GetNationalitiesByNames answers in the order of names, nil for an unknown one.
+	GetNationalitiesByNames(ctx context.Context, names []string) ([]*model.NationalityEstimate, error)
 }
@@ -0,0 +1,14 @@
+package client
+
+import (
+	"context"
+
+	"github.com/ivanjabrony/personApi/internal/model"
+)
+
+// UpstreamMonitor reports how calls to the enrichment upstreams are guarded
+// and probes whether an upstream host is reachable.
+type UpstreamMonitor interface {
+	UpstreamStatuses() []model.UpstreamStatus
+	Probe(ctx context.Context, host string) error
+}
@@ -0,0 +1,27 @@
+package controller
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/ivanjabrony/personApi/internal/service"
+)
+
+type DiagnosticsController struct {
+	diagnosticsService service.DiagnosticsService
+}
+
+func NewDiagnosticsController(diagnosticsService service.DiagnosticsService) *DiagnosticsController {
+	return &DiagnosticsController{diagnosticsService: diagnosticsService}
+}
+
+// GetUpstreamStatuses godoc
+// @Summary      Enrichment upstreams state
+// @Description  Reports circuit breaker, rate limit and remaining quota of every enrichment upstream
+// @Tags         admin
+// @Produce      json
+// @Success      200 {array} dto.UpstreamStatusDto
+// @Router       /admin/enrichment/upstreams [get]
+func (dc *DiagnosticsController) GetUpstreamStatuses(c *gin.Context) {
+	c.JSON(http.StatusOK, dc.diagnosticsService.GetUpstreamStatuses(c.Request.Context()))
+}
@@ -0,0 +1,60 @@
+package controller
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/ivanjabrony/personApi/internal/model"
+	"github.com/ivanjabrony/personApi/internal/model/dto"
+)
+
+var errBadRequest = errors.New("bad request")
+
+func respondWithError(c *gin.Context, err error) {
+	_ = c.Error(err)
+
+	status, response := translateError(err)
+	c.AbortWithStatusJSON(status, response)
+}
+
+func errorResponse(err error) *dto.BadResponseDto {
+	_, response := translateError(err)
+	return &response
+}
+
+func translateError(err error) (int, dto.BadResponseDto) {
+	status, code, message := http.StatusInternalServerError, "internal_error", "Internal server error"
+	switch {
+	case errors.Is(err, errBadRequest):
+		status, code, message = http.StatusBadRequest, "bad_request", err.Error()
+	case errors.Is(err, model.ErrNotFound):
+		status, code, message = http.StatusNotFound, "not_found", err.Error()
+	case errors.Is(err, model.ErrValidation):
+		status, code, message = http.StatusUnprocessableEntity, "validation_failed", err.Error()
+	case errors.Is(err, model.ErrPreconditionFailed):
+		status, code, message = http.StatusPreconditionFailed, "precondition_failed", "Person was modified since it was read, fetch it again"
+	case errors.Is(err, model.ErrPreconditionRequired):
+		status, code, message = http.StatusPreconditionRequired, "precondition_required", err.Error()
+	case errors.Is(err, model.ErrConflict):
+		status, code, message = http.StatusConflict, "conflict", "Person conflicts with existing data"
+	case errors.Is(err, model.ErrUpstreamUnavailable):
+		status, code, message = http.StatusServiceUnavailable, "upstream_unavailable", "Dependent service is unavailable, retry later"
+	}
+
+	response := dto.BadResponseDto{Code: code, Response: message}
+
+	var validationErr *model.ValidationError
+	if errors.As(err, &validationErr) {
+		response.Details = make([]dto.FieldErrorDto, len(validationErr.Fields))
+		for i, field := range validationErr.Fields {
+			response.Details[i] = dto.FieldErrorDto{
+				Field: field.Field,
+				Rule:  field.Rule,
+				Param: field.Param,
+			}
+		}
+	}
+
+	return status, response
+}
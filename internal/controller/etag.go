@@ -0,0 +1,59 @@
+package controller
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/ivanjabrony/personApi/internal/model"
+)
+
+func formatETag(version int) string {
+	return strconv.Quote(strconv.Itoa(version))
+}
+
+func setETag(c *gin.Context, version int) {
+	c.Header("ETag", formatETag(version))
+}
+
+// parseIfMatch returns the version the client expects to modify. Weak
+// validators never match here, as If-Match demands strong comparison.
+func parseIfMatch(c *gin.Context) (int, error) {
+	header := strings.TrimSpace(c.GetHeader("If-Match"))
+	if header == "" {
+		return 0, fmt.Errorf("%w: If-Match header is required", model.ErrPreconditionRequired)
+	}
+	if header == "*" {
+		return model.AnyVersion, nil
+	}
+
+	unquoted, err := strconv.Unquote(header)
+	if err != nil {
+		return 0, fmt.Errorf("%w: malformed If-Match header", errBadRequest)
+	}
+
+	version, err := strconv.Atoi(unquoted)
+	if err != nil || version == model.AnyVersion {
+		return 0, fmt.Errorf("%w: %s", model.ErrPreconditionFailed, header)
+	}
+
+	return version, nil
+}
+
+func matchesIfNoneMatch(c *gin.Context, version int) bool {
+	header := c.GetHeader("If-None-Match")
+	if header == "" {
+		return false
+	}
+
+	etag := formatETag(version)
+	for _, candidate := range strings.Split(header, ",") {
+		candidate = strings.TrimPrefix(strings.TrimSpace(candidate), "W/")
+		if candidate == "*" || candidate == etag {
+			return true
+		}
+	}
+
+	return false
+}
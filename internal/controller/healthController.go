@@ -0,0 +1,45 @@
+package controller
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/ivanjabrony/personApi/internal/model/dto"
+	"github.com/ivanjabrony/personApi/internal/service"
+)
+
+// HealthController serves the probes. They live outside /api and are not
+// part of the docs.
+type HealthController struct {
+	healthService service.HealthService
+}
+
+func NewHealthController(healthService service.HealthService) *HealthController {
+	return &HealthController{healthService: healthService}
+}
+
+// Healthz answers as long as the process serves requests at all.
+func (hc *HealthController) Healthz(c *gin.Context) {
+	c.JSON(http.StatusOK, dto.HealthDto{Status: dto.HealthStatusUp})
+}
+
+// Readyz answers 503 once shutdown has started or when Postgres or the
+// schema is not usable, so no traffic is routed to the instance.
+func (hc *HealthController) Readyz(c *gin.Context) {
+	respondWithHealth(c, hc.healthService.CheckReadiness(c.Request.Context()))
+}
+
+// HealthDetails reports every dependency with its latency. Unavailable
+// enrichment upstreams degrade the status but keep it 200.
+func (hc *HealthController) HealthDetails(c *gin.Context) {
+	respondWithHealth(c, hc.healthService.GetHealthDetails(c.Request.Context()))
+}
+
+func respondWithHealth(c *gin.Context, health *dto.HealthDto) {
+	if health.Status == dto.HealthStatusDown {
+		c.JSON(http.StatusServiceUnavailable, health)
+		return
+	}
+
+	c.JSON(http.StatusOK, health)
+}
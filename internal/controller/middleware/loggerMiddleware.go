@@ -5,6 +5,8 @@ import (
 	"time"
 
 	"github.com/gin-gonic/gin"
+	"github.com/ivanjabrony/personApi/internal/model"
+	"go.opentelemetry.io/otel/trace"
 )
 
 func LoggerMiddleware(logger *slog.Logger) gin.HandlerFunc {
@@ -27,7 +29,17 @@ func LoggerMiddleware(logger *slog.Logger) gin.HandlerFunc {
 			slog.String("path", c.FullPath()),
 			slog.Int("status", statusCode),
 			slog.String("client_ip", c.ClientIP()),
+			slog.String("request_id", model.RequestMetaFromContext(c.Request.Context()).RequestId),
+			slog.String("trace_id", traceId(c)),
 			slog.Duration("duration", duration),
 		)
 	}
 }
+
+func traceId(c *gin.Context) string {
+	spanContext := trace.SpanContextFromContext(c.Request.Context())
+	if !spanContext.HasTraceID() {
+		return ""
+	}
+	return spanContext.TraceID().String()
+}
@@ -0,0 +1,29 @@
+package middleware
+
+import (
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/ivanjabrony/personApi/internal/metrics"
+)
+
+func MetricsMiddleware(m *metrics.Metrics) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		start := time.Now()
+		m.RequestStarted()
+
+		c.Next()
+
+		m.RequestFinished(c.Request.Method, routeLabel(c), strconv.Itoa(c.Writer.Status()), time.Since(start))
+	}
+}
+
+// routeLabel keeps the label set bounded, unknown paths share one value.
+func routeLabel(c *gin.Context) string {
+	if route := c.FullPath(); route != "" {
+		return route
+	}
+
+	return "unmatched"
+}
@@ -0,0 +1,47 @@
+package middleware
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+
+	"github.com/gin-gonic/gin"
+	"github.com/ivanjabrony/personApi/internal/model"
+)
+
+const (
+	RequestIdHeader = "X-Request-Id"
+	ActorHeader     = "X-Actor"
+
+	maxRequestMetaLength = 128
+)
+
+func RequestMetaMiddleware() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		requestId := truncate(c.GetHeader(RequestIdHeader))
+		if requestId == "" {
+			requestId = newRequestId()
+		}
+		c.Header(RequestIdHeader, requestId)
+
+		meta := model.RequestMeta{
+			RequestId: requestId,
+			Actor:     truncate(c.GetHeader(ActorHeader)),
+		}
+		c.Request = c.Request.WithContext(model.ContextWithRequestMeta(c.Request.Context(), meta))
+
+		c.Next()
+	}
+}
+
+func newRequestId() string {
+	buf := make([]byte, 16)
+	_, _ = rand.Read(buf)
+	return hex.EncodeToString(buf)
+}
+
+func truncate(value string) string {
+	if len(value) > maxRequestMetaLength {
+		return value[:maxRequestMetaLength]
+	}
+	return value
+}
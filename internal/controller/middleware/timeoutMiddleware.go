@@ -6,9 +6,11 @@ import (
 	"time"
 
 	"github.com/gin-gonic/gin"
+	"github.com/ivanjabrony/personApi/internal/metrics"
+	"github.com/ivanjabrony/personApi/internal/model/dto"
 )
 
-func TimeoutMiddleware(timeout time.Duration) gin.HandlerFunc {
+func TimeoutMiddleware(timeout time.Duration, m *metrics.Metrics) gin.HandlerFunc {
 	return func(c *gin.Context) {
 		ctx, cancel := context.WithTimeout(c.Request.Context(), timeout)
 		defer cancel()
@@ -30,8 +32,10 @@ func TimeoutMiddleware(timeout time.Duration) gin.HandlerFunc {
 
 		select {
 		case <-ctx.Done():
-			c.AbortWithStatusJSON(http.StatusGatewayTimeout, gin.H{
-				"error": "request timed out",
+			m.RequestTimedOut(c.Request.Method, routeLabel(c))
+			c.AbortWithStatusJSON(http.StatusGatewayTimeout, dto.BadResponseDto{
+				Code:     "timeout",
+				Response: "request timed out",
 			})
 		case p := <-panicChan:
 			panic(p)
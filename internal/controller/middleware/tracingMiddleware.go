@@ -0,0 +1,25 @@
+package middleware
+
+import (
+	"net/http"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/ivanjabrony/personApi/internal/tracing"
+	"go.opentelemetry.io/contrib/instrumentation/github.com/gin-gonic/gin/otelgin"
+)
+
+var untracedPaths = map[string]bool{
+	"/healthz":        true,
+	"/readyz":         true,
+	"/health/details": true,
+	"/metrics":        true,
+}
+
+// TracingMiddleware starts a server span per request, continuing the trace
+// of an incoming traceparent header. Probes, metrics and swagger are skipped.
+func TracingMiddleware() gin.HandlerFunc {
+	return otelgin.Middleware(tracing.ServiceName, otelgin.WithFilter(func(req *http.Request) bool {
+		return !untracedPaths[req.URL.Path] && !strings.HasPrefix(req.URL.Path, "/swagger/")
+	}))
+}
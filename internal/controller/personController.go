@@ -1,9 +1,12 @@
 package controller
 
 import (
+	"encoding/json"
+	"fmt"
 	"net/http"
 	"strconv"
 	"strings"
+	"time"
 
 	"github.com/gin-gonic/gin"
 	"github.com/ivanjabrony/personApi/internal/model"
@@ -12,11 +15,14 @@ import (
 )
 
 type PersonCotroller struct {
-	personService service.PersonService
+	personService     service.PersonService
+	enrichmentService service.EnrichmentService
+	purgeRetention    time.Duration
+	batchMaxSize      int
 }
 
-func NewPersonController(personService service.PersonService) *PersonCotroller {
-	return &PersonCotroller{personService: personService}
+func NewPersonController(personService service.PersonService, enrichmentService service.EnrichmentService, purgeRetention time.Duration, batchMaxSize int) *PersonCotroller {
+	return &PersonCotroller{personService: personService, enrichmentService: enrichmentService, purgeRetention: purgeRetention, batchMaxSize: batchMaxSize}
 }
 
 // GetPerson godoc
@@ -26,25 +32,38 @@ func NewPersonController(personService service.PersonService) *PersonCotroller {
 // @Accept       json
 // @Produce      json
 // @Param        id path int true "ID of person"
+// @Param        include_deleted query bool false "Return the person even if it is soft deleted"
+// @Param        If-None-Match header string false "ETag of cached representation"
 // @Success      200 {object} dto.PersonDto
+// @Success      304 "Not modified"
+// @Header       200 {string} ETag "Version of the person"
 // @Failure      400 {object} dto.BadResponseDto
+// @Failure      404 {object} dto.BadResponseDto
+// @Failure      500 {object} dto.BadResponseDto
+// @Failure      503 {object} dto.BadResponseDto
 // @Router       /persons/{id} [get]
 func (pc *PersonCotroller) GetPerson(c *gin.Context) {
-	id, exists := c.Params.Get("id")
-	if !exists {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "No iD provided"})
+	parsedId, err := parseId(c)
+	if err != nil {
+		respondWithError(c, err)
 		return
 	}
 
-	parsedId, err := strconv.Atoi(id)
+	includeDeleted, err := parseBoolQuery(c, "include_deleted")
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to parse ID"})
+		respondWithError(c, err)
 		return
 	}
 
-	person, err := pc.personService.GetPersonById(c.Request.Context(), parsedId)
+	person, err := pc.personService.GetPersonById(c.Request.Context(), parsedId, includeDeleted)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to retrieve person info"})
+		respondWithError(c, err)
+		return
+	}
+
+	setETag(c, person.Version)
+	if matchesIfNoneMatch(c, person.Version) {
+		c.Status(http.StatusNotModified)
 		return
 	}
 
@@ -59,40 +78,25 @@ func (pc *PersonCotroller) GetPerson(c *gin.Context) {
 // @Produce      json
 // @Param page query int false "Page number (starting from 1)" default(1)
 // @Param page_size query int false "Amount of items on the page" default(10) minimum(1) maximum(100)
+// @Param cursor query string false "Opaque cursor taken from next_cursor of the previous page"
 // @Success      200 {object} dto.PaginatedPersonsDto
 // @Failure      400 {object} dto.BadResponseDto
+// @Failure      500 {object} dto.BadResponseDto
+// @Failure      503 {object} dto.BadResponseDto
 // @Router       /persons [get]
 func (pc *PersonCotroller) GetAllPersons(c *gin.Context) {
-	page, _ := strconv.Atoi(c.DefaultQuery("page", "1"))
-	pageSize, _ := strconv.Atoi(c.DefaultQuery("page_size", "10"))
-
-	if page < 1 {
-		page = 1
-	}
-	if pageSize < 1 || pageSize > 50 {
-		pageSize = 10
-	}
-
-	offset := (page - 1) * pageSize
-	persons, err := pc.personService.GetAllPersons(c.Request.Context())
+	pagination, err := parsePagination(c, nil)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to retrieve person info"})
+		respondWithError(c, err)
 		return
 	}
 
-	total := len(persons)
-	totalPages := total / pageSize
-	if total%pageSize != 0 {
-		totalPages++
+	response, err := pc.personService.GetAllPersons(c.Request.Context(), pagination)
+	if err != nil {
+		respondWithError(c, err)
+		return
 	}
 
-	response := dto.PaginatedPersonsDto{
-		Data:       persons[offset:min(len(persons), offset+pageSize)],
-		Page:       page,
-		PageSize:   pageSize,
-		Total:      total,
-		TotalPages: totalPages,
-	}
 	c.JSON(http.StatusOK, response)
 }
 
@@ -112,10 +116,16 @@ func (pc *PersonCotroller) GetAllPersons(c *gin.Context) {
 // @Param 		 patronymic_like query string false "Patronymic pattern to match" example("Vl%")
 // @Param 		 age_min query int false "Min wanted age" minimum(0)
 // @Param 		 age_max query int false "Max wanted age" maximum(110)
+// @Param 		 include_deleted query bool false "Include soft deleted persons"
+// @Param 		 sort query string false "Comma separated columns to sort by, prefixed with - for descending order" example("-age,surname")
 // @Param 		 page query int false "Page number (starting from 1)" default(1)
 // @Param 		 page_size query int false "Amount of items on the page" default(10) minimum(1) maximum(100)
+// @Param 		 cursor query string false "Opaque cursor taken from next_cursor of the previous page"
 // @Success      200 {object} dto.PaginatedPersonsDto
 // @Failure      400 {object} dto.BadResponseDto
+// @Failure      422 {object} dto.BadResponseDto
+// @Failure      500 {object} dto.BadResponseDto
+// @Failure      503 {object} dto.BadResponseDto
 // @Router       /persons/filtered [get]
 func (pc *PersonCotroller) GetFilteredPesons(c *gin.Context) {
 	var filter model.PersonFilter
@@ -146,134 +156,511 @@ func (pc *PersonCotroller) GetFilteredPesons(c *gin.Context) {
 		filter.PatronymicLike = &patronymic_like
 	}
 	if ageMin := c.Query("age_min"); ageMin != "" {
-		if val, err := strconv.Atoi(ageMin); err == nil {
-			filter.AgeMin = &val
+		val, err := strconv.Atoi(ageMin)
+		if err != nil {
+			respondWithError(c, fmt.Errorf("%w: age_min must be an integer", errBadRequest))
+			return
 		}
+		filter.AgeMin = &val
 	}
 	if ageMax := c.Query("age_max"); ageMax != "" {
-		if val, err := strconv.Atoi(ageMax); err == nil {
-			filter.AgeMax = &val
+		val, err := strconv.Atoi(ageMax)
+		if err != nil {
+			respondWithError(c, fmt.Errorf("%w: age_max must be an integer", errBadRequest))
+			return
 		}
+		filter.AgeMax = &val
 	}
 
-	page, _ := strconv.Atoi(c.DefaultQuery("page", "1"))
-	pageSize, _ := strconv.Atoi(c.DefaultQuery("page_size", "10"))
-
-	if page < 1 {
-		page = 1
-	}
-	if pageSize < 1 || pageSize > 50 {
-		pageSize = 10
+	includeDeleted, err := parseBoolQuery(c, "include_deleted")
+	if err != nil {
+		respondWithError(c, err)
+		return
 	}
+	filter.IncludeDeleted = includeDeleted
 
-	offset := (page - 1) * pageSize
+	if sort := c.Query("sort"); sort != "" {
+		parsed, err := model.ParseSort(sort)
+		if err != nil {
+			respondWithError(c, fmt.Errorf("%w: %w", errBadRequest, err))
+			return
+		}
+		filter.Sort = parsed
+	}
 
-	persons, err := pc.personService.GetPersonsFiltered(c.Request.Context(), &filter)
+	pagination, err := parsePagination(c, filter.Sort)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to retrieve persons info"})
+		respondWithError(c, err)
 		return
 	}
 
-	total := len(persons)
-	totalPages := total / pageSize
-	if total%pageSize != 0 {
-		totalPages++
+	response, err := pc.personService.GetPersonsFiltered(c.Request.Context(), &filter, pagination)
+	if err != nil {
+		respondWithError(c, err)
+		return
 	}
 
-	response := dto.PaginatedPersonsDto{
-		Data:       persons[offset:min(len(persons), offset+pageSize)],
-		Page:       page,
-		PageSize:   pageSize,
-		Total:      total,
-		TotalPages: totalPages,
-	}
 	c.JSON(http.StatusOK, response)
 }
 
 // CreatePerson godoc
 // @Summary     Create person
-// @Description Creates new person
+// @Description Creates new person, age, gender and nationality are looked up in the background
 // @Tags        person
 // @Accept      json
 // @Produce     json
 // @Param       request body dto.NewPersonDto true "Person data"
+// @Param       X-Actor header string false "Who performs the change, recorded in the audit trail"
 // @Success     204 "Creating Success"
 // @Failure     400 {object} dto.BadResponseDto
+// @Failure     409 {object} dto.BadResponseDto
+// @Failure     422 {object} dto.BadResponseDto
+// @Failure     500 {object} dto.BadResponseDto
+// @Failure     503 {object} dto.BadResponseDto
 // @Router      /persons [post]
 func (pc *PersonCotroller) CreatePerson(c *gin.Context) {
 	var createDto dto.NewPersonDto
 
-	err := c.BindJSON(&createDto)
+	err := bindPayload(c, &createDto)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to parse data for creating"})
+		respondWithError(c, err)
 		return
 	}
 
 	id, err := pc.personService.CreatePerson(c.Request.Context(), &createDto)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to create person"})
+		respondWithError(c, err)
 		return
 	}
 
 	c.JSON(http.StatusOK, id)
 }
 
+// CreatePersons godoc
+// @Summary     Create persons in batch
+// @Description Creates up to the configured amount of persons at once, queueing their enrichment. Invalid items are reported and skipped
+// @Tags        person
+// @Accept      json
+// @Produce     json
+// @Param       request body []dto.NewPersonDto true "Persons data"
+// @Param       X-Actor header string false "Who performs the change, recorded in the audit trail"
+// @Success     200 {object} dto.BatchCreateResultDto
+// @Failure     400 {object} dto.BadResponseDto
+// @Failure     409 {object} dto.BadResponseDto
+// @Failure     413 {object} dto.BadResponseDto
+// @Failure     500 {object} dto.BadResponseDto
+// @Failure     503 {object} dto.BadResponseDto
+// @Router      /persons/batch [post]
+func (pc *PersonCotroller) CreatePersons(c *gin.Context) {
+	var createDtos []dto.NewPersonDto
+
+	err := json.NewDecoder(c.Request.Body).Decode(&createDtos)
+	if err != nil {
+		respondWithError(c, fmt.Errorf("%w: malformed request body: %w", errBadRequest, err))
+		return
+	}
+
+	if len(createDtos) == 0 {
+		respondWithError(c, fmt.Errorf("%w: batch is empty", errBadRequest))
+		return
+	}
+	if len(createDtos) > pc.batchMaxSize {
+		c.AbortWithStatusJSON(http.StatusRequestEntityTooLarge, dto.BadResponseDto{
+			Code:     "batch_too_large",
+			Response: fmt.Sprintf("batch must contain at most %d persons", pc.batchMaxSize),
+		})
+		return
+	}
+
+	response := dto.BatchCreateResultDto{Results: make([]dto.BatchItemResultDto, len(createDtos))}
+	valid := make([]dto.NewPersonDto, 0, len(createDtos))
+	validIndexes := make([]int, 0, len(createDtos))
+
+	for i := range createDtos {
+		response.Results[i].Index = i
+
+		if err := validatePayload(&createDtos[i]); err != nil {
+			response.Results[i].Error = errorResponse(err)
+			response.Failed++
+			continue
+		}
+
+		valid = append(valid, createDtos[i])
+		validIndexes = append(validIndexes, i)
+	}
+
+	if len(valid) != 0 {
+		ids, err := pc.personService.CreatePersons(c.Request.Context(), valid)
+		if err != nil {
+			respondWithError(c, err)
+			return
+		}
+
+		for i, id := range ids {
+			response.Results[validIndexes[i]].Id = &id
+			response.Created++
+		}
+	}
+
+	c.JSON(http.StatusOK, response)
+}
+
 // UpdatePerson godoc
-// @Summary      Update user
-// @Description  Updates existing user
+// @Summary      Replace person
+// @Description  Replaces all editable fields of existing person, omitted optional fields are cleared
 // @Tags         person
 // @Accept       json
 // @Produce      json
-// @Param        request body dto.UpdatePersonDto true "Updated data"
-// @Success      204 "Update success"
+// @Param        id path int true "Person ID"
+// @Param        If-Match header string true "ETag of the person being replaced or *"
+// @Param        request body dto.UpdatePersonDto true "Replacement data"
+// @Param        X-Actor header string false "Who performs the change, recorded in the audit trail"
+// @Success      200 {object} dto.PersonDto
+// @Header       200 {string} ETag "New version of the person"
 // @Failure      400 {object} dto.BadResponseDto
-// @Router       /persons [put]
+// @Failure      404 {object} dto.BadResponseDto
+// @Failure      409 {object} dto.BadResponseDto
+// @Failure      412 {object} dto.BadResponseDto
+// @Failure      422 {object} dto.BadResponseDto
+// @Failure      428 {object} dto.BadResponseDto
+// @Failure      500 {object} dto.BadResponseDto
+// @Failure      503 {object} dto.BadResponseDto
+// @Router       /persons/{id} [put]
 func (pc *PersonCotroller) UpdatePerson(c *gin.Context) {
+	parsedId, err := parseId(c)
+	if err != nil {
+		respondWithError(c, err)
+		return
+	}
+
+	version, err := parseIfMatch(c)
+	if err != nil {
+		respondWithError(c, err)
+		return
+	}
+
 	var updateDto dto.UpdatePersonDto
 
-	err := c.BindJSON(&updateDto)
+	err = bindPayload(c, &updateDto)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to parse data for updating"})
+		respondWithError(c, err)
 		return
 	}
 
-	err = pc.personService.UpdatePersonById(c.Request.Context(), &updateDto)
+	person, err := pc.personService.UpdatePersonById(c.Request.Context(), parsedId, version, &updateDto)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to update person info"})
+		respondWithError(c, err)
 		return
 	}
 
-	c.JSON(http.StatusOK, updateDto.Id)
+	setETag(c, person.Version)
+	c.JSON(http.StatusOK, person)
+}
+
+// PatchPerson godoc
+// @Summary      Partially update person
+// @Description  Applies JSON Merge Patch (RFC 7396): absent fields are untouched, null clears patronymic, age, gender or nationality
+// @Tags         person
+// @Accept       json
+// @Accept       application/merge-patch+json
+// @Produce      json
+// @Param        id path int true "Person ID"
+// @Param        If-Match header string true "ETag of the person being patched or *"
+// @Param        request body dto.PatchPersonDto true "Merge patch"
+// @Param        X-Actor header string false "Who performs the change, recorded in the audit trail"
+// @Success      200 {object} dto.PersonDto
+// @Header       200 {string} ETag "New version of the person"
+// @Failure      400 {object} dto.BadResponseDto
+// @Failure      404 {object} dto.BadResponseDto
+// @Failure      409 {object} dto.BadResponseDto
+// @Failure      412 {object} dto.BadResponseDto
+// @Failure      422 {object} dto.BadResponseDto
+// @Failure      428 {object} dto.BadResponseDto
+// @Failure      500 {object} dto.BadResponseDto
+// @Failure      503 {object} dto.BadResponseDto
+// @Router       /persons/{id} [patch]
+func (pc *PersonCotroller) PatchPerson(c *gin.Context) {
+	parsedId, err := parseId(c)
+	if err != nil {
+		respondWithError(c, err)
+		return
+	}
+
+	version, err := parseIfMatch(c)
+	if err != nil {
+		respondWithError(c, err)
+		return
+	}
+
+	var patchDto dto.PatchPersonDto
+
+	err = bindPayload(c, &patchDto)
+	if err != nil {
+		respondWithError(c, err)
+		return
+	}
+
+	person, err := pc.personService.PatchPersonById(c.Request.Context(), parsedId, version, &patchDto)
+	if err != nil {
+		respondWithError(c, err)
+		return
+	}
+
+	setETag(c, person.Version)
+	c.JSON(http.StatusOK, person)
 }
 
 // DeletePerson godoc
 // @Summary      Delete person
-// @Description  Deletes person by ID
+// @Description  Soft deletes person by ID, it can be restored until purged
 // @Tags         person
 // @Accept       json
 // @Produce      json
 // @Param        id path int true "Person ID"
+// @Param        If-Match header string true "ETag of the person being deleted or *"
+// @Param        X-Actor header string false "Who performs the change, recorded in the audit trail"
 // @Success      204 "Delete success"
 // @Failure      400 {object} dto.BadResponseDto
+// @Failure      404 {object} dto.BadResponseDto
+// @Failure      412 {object} dto.BadResponseDto
+// @Failure      428 {object} dto.BadResponseDto
+// @Failure      500 {object} dto.BadResponseDto
+// @Failure      503 {object} dto.BadResponseDto
 // @Router       /persons/{id} [delete]
 func (pc *PersonCotroller) DeletePersonById(c *gin.Context) {
-	id, exists := c.Params.Get("id")
-	if !exists {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "No iD provided"})
+	parsedId, err := parseId(c)
+	if err != nil {
+		respondWithError(c, err)
 		return
 	}
 
-	parsedId, err := strconv.Atoi(id)
+	version, err := parseIfMatch(c)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to parse ID"})
+		respondWithError(c, err)
 		return
 	}
 
-	err = pc.personService.DeletePersonById(c.Request.Context(), parsedId)
+	err = pc.personService.DeletePersonById(c.Request.Context(), parsedId, version)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to delete person data"})
+		respondWithError(c, err)
 		return
 	}
 
-	c.JSON(http.StatusOK, id)
+	c.JSON(http.StatusOK, parsedId)
+}
+
+// RestorePerson godoc
+// @Summary      Restore person
+// @Description  Restores soft deleted person by ID
+// @Tags         person
+// @Produce      json
+// @Param        id path int true "Person ID"
+// @Param        X-Actor header string false "Who performs the change, recorded in the audit trail"
+// @Success      200 {object} dto.PersonDto
+// @Header       200 {string} ETag "New version of the person"
+// @Failure      400 {object} dto.BadResponseDto
+// @Failure      404 {object} dto.BadResponseDto
+// @Failure      409 {object} dto.BadResponseDto
+// @Failure      500 {object} dto.BadResponseDto
+// @Failure      503 {object} dto.BadResponseDto
+// @Router       /persons/{id}/restore [post]
+func (pc *PersonCotroller) RestorePerson(c *gin.Context) {
+	parsedId, err := parseId(c)
+	if err != nil {
+		respondWithError(c, err)
+		return
+	}
+
+	person, err := pc.personService.RestorePersonById(c.Request.Context(), parsedId)
+	if err != nil {
+		respondWithError(c, err)
+		return
+	}
+
+	setETag(c, person.Version)
+	c.JSON(http.StatusOK, person)
+}
+
+// GetPersonHistory godoc
+// @Summary      Get person change history
+// @Description  returning audit trail of person mutations, newest first
+// @Tags         person
+// @Produce      json
+// @Param        id path int true "Person ID"
+// @Param        page query int false "Page number (starting from 1)" default(1)
+// @Param        page_size query int false "Amount of items on the page" default(10) minimum(1) maximum(100)
+// @Success      200 {object} dto.PaginatedPersonAuditDto
+// @Failure      400 {object} dto.BadResponseDto
+// @Failure      500 {object} dto.BadResponseDto
+// @Failure      503 {object} dto.BadResponseDto
+// @Router       /persons/{id}/history [get]
+func (pc *PersonCotroller) GetPersonHistory(c *gin.Context) {
+	parsedId, err := parseId(c)
+	if err != nil {
+		respondWithError(c, err)
+		return
+	}
+
+	response, err := pc.personService.GetPersonHistory(c.Request.Context(), parsedId, parsePage(c))
+	if err != nil {
+		respondWithError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, response)
+}
+
+// GetEnrichmentStatus godoc
+// @Summary      Get person enrichment status
+// @Description  returning state of the background lookup of age, gender and nationality
+// @Tags         person
+// @Produce      json
+// @Param        id path int true "Person ID"
+// @Success      200 {object} dto.EnrichmentStatusDto
+// @Failure      400 {object} dto.BadResponseDto
+// @Failure      404 {object} dto.BadResponseDto
+// @Failure      500 {object} dto.BadResponseDto
+// @Failure      503 {object} dto.BadResponseDto
+// @Router       /persons/{id}/enrichment [get]
+func (pc *PersonCotroller) GetEnrichmentStatus(c *gin.Context) {
+	parsedId, err := parseId(c)
+	if err != nil {
+		respondWithError(c, err)
+		return
+	}
+
+	response, err := pc.enrichmentService.GetEnrichmentStatus(c.Request.Context(), parsedId)
+	if err != nil {
+		respondWithError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, response)
+}
+
+// EnrichPerson godoc
+// @Summary      Enrich person again
+// @Description  Queues a fresh lookup of age, gender and nationality that replaces the current values once it succeeds
+// @Tags         person
+// @Produce      json
+// @Param        id path int true "Person ID"
+// @Success      202 {object} dto.EnrichmentStatusDto
+// @Failure      400 {object} dto.BadResponseDto
+// @Failure      404 {object} dto.BadResponseDto
+// @Failure      500 {object} dto.BadResponseDto
+// @Failure      503 {object} dto.BadResponseDto
+// @Router       /persons/{id}/enrich [post]
+func (pc *PersonCotroller) EnrichPerson(c *gin.Context) {
+	parsedId, err := parseId(c)
+	if err != nil {
+		respondWithError(c, err)
+		return
+	}
+
+	response, err := pc.enrichmentService.RequestEnrichment(c.Request.Context(), parsedId)
+	if err != nil {
+		respondWithError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusAccepted, response)
+}
+
+// PurgeDeletedPersons godoc
+// @Summary      Purge deleted persons
+// @Description  Permanently removes persons soft deleted longer than the retention window ago
+// @Tags         admin
+// @Produce      json
+// @Param        older_than query string false "Retention window as Go duration, at least and by default the configured one" example("720h")
+// @Param        X-Actor header string false "Who performs the change, recorded in the audit trail"
+// @Success      200 {object} dto.PurgeResultDto
+// @Failure      400 {object} dto.BadResponseDto
+// @Failure      500 {object} dto.BadResponseDto
+// @Failure      503 {object} dto.BadResponseDto
+// @Router       /admin/persons/purge [post]
+func (pc *PersonCotroller) PurgeDeletedPersons(c *gin.Context) {
+	retention := pc.purgeRetention
+	if olderThan := c.Query("older_than"); olderThan != "" {
+		parsed, err := time.ParseDuration(olderThan)
+		if err != nil || parsed < 0 {
+			respondWithError(c, fmt.Errorf("%w: older_than must be a non-negative duration", errBadRequest))
+			return
+		}
+		// the window can only be widened, so a request can't purge recently deleted persons
+		if parsed < pc.purgeRetention {
+			respondWithError(c, fmt.Errorf("%w: older_than must not be shorter than the retention of %s", errBadRequest, pc.purgeRetention))
+			return
+		}
+		retention = parsed
+	}
+
+	result, err := pc.personService.PurgeDeletedPersons(c.Request.Context(), retention)
+	if err != nil {
+		respondWithError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, result)
+}
+
+func parseId(c *gin.Context) (int, error) {
+	id, exists := c.Params.Get("id")
+	if !exists {
+		return 0, fmt.Errorf("%w: no id provided", errBadRequest)
+	}
+
+	parsedId, err := strconv.Atoi(id)
+	if err != nil {
+		return 0, fmt.Errorf("%w: id must be an integer", errBadRequest)
+	}
+
+	return parsedId, nil
+}
+
+func parsePage(c *gin.Context) *model.Pagination {
+	page, _ := strconv.Atoi(c.DefaultQuery("page", "1"))
+	pageSize, _ := strconv.Atoi(c.DefaultQuery("page_size", "10"))
+
+	if page < 1 {
+		page = 1
+	}
+	if pageSize < 1 || pageSize > 50 {
+		pageSize = 10
+	}
+
+	return &model.Pagination{
+		Page:     page,
+		PageSize: pageSize,
+	}
+}
+
+func parsePagination(c *gin.Context, sort []model.SortField) (*model.Pagination, error) {
+	pagination := parsePage(c)
+
+	if cursor := c.Query("cursor"); cursor != "" {
+		decoded, err := model.DecodeCursor(cursor)
+		if err != nil {
+			return nil, fmt.Errorf("%w: %w", errBadRequest, err)
+		}
+		if decoded.Sort != model.FormatSort(model.EffectiveSort(sort)) {
+			return nil, fmt.Errorf("%w: cursor was issued for a different sort order", errBadRequest)
+		}
+		pagination.Cursor = decoded
+	}
+
+	return pagination, nil
+}
+
+func parseBoolQuery(c *gin.Context, name string) (bool, error) {
+	value := c.Query(name)
+	if value == "" {
+		return false, nil
+	}
+
+	parsed, err := strconv.ParseBool(value)
+	if err != nil {
+		return false, fmt.Errorf("%w: %s must be a boolean", errBadRequest, name)
+	}
+
+	return parsed, nil
 }
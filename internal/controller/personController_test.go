@@ -0,0 +1,85 @@
+package controller
+
+import (
+	"errors"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/ivanjabrony/personApi/internal/model"
+)
+
+func TestParsePagination(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	byAge := []model.SortField{{Column: "age", Desc: true}}
+	ageCursor := (&model.Cursor{Sort: "-age,id", Keys: []any{30.0}, Id: 7}).Encode()
+
+	tests := []struct {
+		name       string
+		query      url.Values
+		sort       []model.SortField
+		wantPage   model.Pagination
+		wantCursor bool
+		wantErr    bool
+	}{
+		{
+			name:     "defaults",
+			wantPage: model.Pagination{Page: 1, PageSize: 10},
+		},
+		{
+			name:     "out of range page size falls back",
+			query:    url.Values{"page": {"3"}, "page_size": {"500"}},
+			wantPage: model.Pagination{Page: 3, PageSize: 10},
+		},
+		{
+			name:       "cursor for the same sort",
+			query:      url.Values{"cursor": {ageCursor}},
+			sort:       byAge,
+			wantPage:   model.Pagination{Page: 1, PageSize: 10},
+			wantCursor: true,
+		},
+		{
+			name:    "cursor for another sort",
+			query:   url.Values{"cursor": {ageCursor}},
+			sort:    []model.SortField{{Column: "age"}},
+			wantErr: true,
+		},
+		{
+			name:    "cursor without sort",
+			query:   url.Values{"cursor": {ageCursor}},
+			wantErr: true,
+		},
+		{
+			name:    "malformed cursor",
+			query:   url.Values{"cursor": {"%%%"}},
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c, _ := gin.CreateTestContext(httptest.NewRecorder())
+			c.Request = httptest.NewRequest("GET", "/api/persons/?"+tt.query.Encode(), nil)
+
+			got, err := parsePagination(c, tt.sort)
+			if tt.wantErr {
+				if !errors.Is(err, errBadRequest) {
+					t.Fatalf("parsePagination() error = %v, want %v", err, errBadRequest)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("parsePagination() error = %v", err)
+			}
+
+			if got.Page != tt.wantPage.Page || got.PageSize != tt.wantPage.PageSize {
+				t.Errorf("page = %d/%d, want %d/%d", got.Page, got.PageSize, tt.wantPage.Page, tt.wantPage.PageSize)
+			}
+			if (got.Cursor != nil) != tt.wantCursor {
+				t.Errorf("cursor = %+v, want cursor %t", got.Cursor, tt.wantCursor)
+			}
+		})
+	}
+}
@@ -1,6 +1,7 @@
 package controller
 
 import (
+	"fmt"
 	"log/slog"
 	"os"
 	"strconv"
@@ -10,14 +11,26 @@ import (
 
 	"github.com/gin-gonic/gin"
 	"github.com/ivanjabrony/personApi/internal/controller/middleware"
+	"github.com/ivanjabrony/personApi/internal/metrics"
 	"github.com/ivanjabrony/personApi/internal/service"
 	swaggerFiles "github.com/swaggo/files"
 	ginSwagger "github.com/swaggo/gin-swagger"
 )
 
-func SetupRouter(logger *slog.Logger, personService service.PersonService) *gin.Engine {
+type Config struct {
+	// PurgeRetention is how long soft deleted persons are kept by default.
+	PurgeRetention time.Duration
+	// BatchMaxSize caps the persons of one batch create request.
+	BatchMaxSize int
+}
+
+func SetupRouter(logger *slog.Logger, personService service.PersonService, enrichmentService service.EnrichmentService, diagnosticsService service.DiagnosticsService, healthService service.HealthService, m *metrics.Metrics, config Config) *gin.Engine {
 	r := gin.Default()
 
+	if err := registerValidators(); err != nil {
+		panic(fmt.Sprintf("failed to register validators: %v", err))
+	}
+
 	timeoutTime := os.Getenv("TIMEOUT_TIME")
 	if timeoutTime == "" {
 		timeoutTime = "3"
@@ -27,10 +40,15 @@ func SetupRouter(logger *slog.Logger, personService service.PersonService) *gin.
 		timeoutParsed = 3
 	}
 
+	r.Use(middleware.TracingMiddleware())
+	r.Use(middleware.MetricsMiddleware(m))
 	r.Use(middleware.LoggerMiddleware(logger))
-	r.Use(middleware.TimeoutMiddleware(time.Duration(timeoutParsed) * time.Second))
+	r.Use(middleware.RequestMetaMiddleware())
+	r.Use(middleware.TimeoutMiddleware(time.Duration(timeoutParsed)*time.Second, m))
 
-	personCotroller := NewPersonController(personService)
+	personCotroller := NewPersonController(personService, enrichmentService, config.PurgeRetention, config.BatchMaxSize)
+	diagnosticsController := NewDiagnosticsController(diagnosticsService)
+	healthController := NewHealthController(healthService)
 
 	port := os.Getenv("PORT")
 	if port == "" {
@@ -41,14 +59,29 @@ func SetupRouter(logger *slog.Logger, personService service.PersonService) *gin.
 	docs.SwaggerInfo.BasePath = "/api"
 
 	r.GET("/swagger/*any", ginSwagger.WrapHandler(swaggerFiles.Handler))
+	r.GET("/healthz", healthController.Healthz)
+	r.GET("/readyz", healthController.Readyz)
+	r.GET("/health/details", healthController.HealthDetails)
+	r.GET("/metrics", gin.WrapH(m.Handler()))
 	api := r.Group("/api/persons")
 
 	api.POST("/", personCotroller.CreatePerson)
-	api.PUT("/", personCotroller.UpdatePerson)
+	api.POST("/batch", personCotroller.CreatePersons)
+	api.PUT("/:id", personCotroller.UpdatePerson)
+	api.PATCH("/:id", personCotroller.PatchPerson)
 	api.GET("/:id", personCotroller.GetPerson)
 	api.DELETE("/:id", personCotroller.DeletePersonById)
 	api.GET("/", personCotroller.GetAllPersons)
 	api.GET("/filtered", personCotroller.GetFilteredPesons)
+	api.POST("/:id/restore", personCotroller.RestorePerson)
+	api.GET("/:id/history", personCotroller.GetPersonHistory)
+	api.GET("/:id/enrichment", personCotroller.GetEnrichmentStatus)
+	api.POST("/:id/enrich", personCotroller.EnrichPerson)
+
+	admin := r.Group("/api/admin/persons")
+	admin.POST("/purge", personCotroller.PurgeDeletedPersons)
+
+	r.GET("/api/admin/enrichment/upstreams", diagnosticsController.GetUpstreamStatuses)
 
 	return r
 }
@@ -0,0 +1,84 @@
+package controller
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"reflect"
+	"regexp"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/gin-gonic/gin/binding"
+	"github.com/go-playground/validator/v10"
+	"github.com/ivanjabrony/personApi/internal/model"
+)
+
+var personNameRegexp = regexp.MustCompile(`^[\p{Latin}\p{Cyrillic}]+(?:[ '’-][\p{Latin}\p{Cyrillic}]+)*$`)
+
+type normalizer interface {
+	Normalize()
+}
+
+type validationValuer interface {
+	ValidationValue() any
+}
+
+func registerValidators() error {
+	v, ok := binding.Validator.Engine().(*validator.Validate)
+	if !ok {
+		return errors.New("unexpected validator engine")
+	}
+
+	v.RegisterTagNameFunc(func(field reflect.StructField) string {
+		name := strings.SplitN(field.Tag.Get("json"), ",", 2)[0]
+		if name == "-" {
+			return ""
+		}
+		return name
+	})
+
+	v.RegisterCustomTypeFunc(func(field reflect.Value) any {
+		return field.Interface().(validationValuer).ValidationValue()
+	}, model.Optional[string]{}, model.Optional[int]{})
+
+	return v.RegisterValidation("personname", func(fl validator.FieldLevel) bool {
+		return personNameRegexp.MatchString(fl.Field().String())
+	})
+}
+
+func bindPayload(c *gin.Context, payload normalizer) error {
+	if err := json.NewDecoder(c.Request.Body).Decode(payload); err != nil {
+		return fmt.Errorf("%w: malformed request body: %w", errBadRequest, err)
+	}
+
+	return validatePayload(payload)
+}
+
+func validatePayload(payload normalizer) error {
+	payload.Normalize()
+
+	if err := binding.Validator.ValidateStruct(payload); err != nil {
+		return toValidationError(err)
+	}
+
+	return nil
+}
+
+func toValidationError(err error) error {
+	var validationErrors validator.ValidationErrors
+	if !errors.As(err, &validationErrors) {
+		return fmt.Errorf("%w: %w", errBadRequest, err)
+	}
+
+	fields := make([]model.FieldError, len(validationErrors))
+	for i, fieldError := range validationErrors {
+		fields[i] = model.FieldError{
+			Field: fieldError.Field(),
+			Rule:  fieldError.Tag(),
+			Param: fieldError.Param(),
+		}
+	}
+
+	return &model.ValidationError{Fields: fields}
+}
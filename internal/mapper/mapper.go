@@ -8,9 +8,10 @@ import (
 func MapFromNewPersonDto(dto *dto.NewPersonDto) *model.Person {
 	if dto != nil {
 		return &model.Person{
-			Name:       dto.Name,
-			Surname:    dto.Surname,
-			Patronymic: dto.Patronymic,
+			Name:        dto.Name,
+			Surname:     dto.Surname,
+			Patronymic:  dto.Patronymic,
+			CountryHint: dto.CountryHint,
 		}
 	}
 
@@ -20,33 +21,52 @@ func MapFromNewPersonDto(dto *dto.NewPersonDto) *model.Person {
 func MapFromPersonDto(dto *dto.PersonDto) *model.Person {
 	if dto != nil {
 		return &model.Person{
-			Id:          dto.Id,
+			Id:               dto.Id,
+			Name:             dto.Name,
+			Surname:          dto.Surname,
+			Patronymic:       dto.Patronymic,
+			Age:              dto.Age,
+			Gender:           dto.Gender,
+			Nationality:      dto.Nationality,
+			Version:          dto.Version,
+			DeletedAt:        dto.DeletedAt,
+			EnrichmentStatus: dto.EnrichmentStatus,
+			EnrichedAt:       dto.EnrichedAt,
+			CountryHint:      dto.CountryHint,
+		}
+	}
+
+	return nil
+}
+
+func MapFromUpdatePersonDto(id int, version int, dto *dto.UpdatePersonDto) *model.Person {
+	if dto != nil {
+		return &model.Person{
+			Id:          id,
 			Name:        dto.Name,
 			Surname:     dto.Surname,
 			Patronymic:  dto.Patronymic,
 			Age:         dto.Age,
 			Gender:      dto.Gender,
 			Nationality: dto.Nationality,
+			Version:     version,
 		}
 	}
 
 	return nil
 }
 
-func MapFromUpdatePersonDto(dto *dto.UpdatePersonDto) *model.Person {
-	var updName, updSurname string
-	if dto.Name != nil {
-		updName = *dto.Name
-	}
-	if dto.Surname != nil {
-		updSurname = *dto.Surname
-	}
+func MapFromPatchPersonDto(id int, version int, dto *dto.PatchPersonDto) *model.PersonPatch {
 	if dto != nil {
-		return &model.Person{
-			Id:         dto.Id,
-			Name:       updName,
-			Surname:    updSurname,
-			Patronymic: dto.Patronymic,
+		return &model.PersonPatch{
+			Id:          id,
+			Name:        dto.Name,
+			Surname:     dto.Surname,
+			Patronymic:  dto.Patronymic,
+			Age:         dto.Age,
+			Gender:      dto.Gender,
+			Nationality: dto.Nationality,
+			Version:     version,
 		}
 	}
 
@@ -56,13 +76,19 @@ func MapFromUpdatePersonDto(dto *dto.UpdatePersonDto) *model.Person {
 func MapToPersonDto(model *model.Person) *dto.PersonDto {
 	if model != nil {
 		return &dto.PersonDto{
-			Id:          model.Id,
-			Name:        model.Name,
-			Surname:     model.Surname,
-			Patronymic:  model.Patronymic,
-			Age:         model.Age,
-			Gender:      model.Gender,
-			Nationality: model.Nationality,
+			Id:               model.Id,
+			Name:             model.Name,
+			Surname:          model.Surname,
+			Patronymic:       model.Patronymic,
+			Age:              model.Age,
+			Gender:           model.Gender,
+			Nationality:      model.Nationality,
+			Version:          model.Version,
+			DeletedAt:        model.DeletedAt,
+			EnrichmentStatus: model.EnrichmentStatus,
+			EnrichedAt:       model.EnrichedAt,
+			CountryHint:      model.CountryHint,
+			Enrichment:       MapToEnrichmentDto(model.Candidates),
 		}
 	}
 
@@ -86,3 +112,119 @@ func MapFromManyPersonDto(dtos ...dto.PersonDto) []model.Person {
 
 	return models
 }
+
+func MapToPaginatedPersonsDto(page *model.PersonPage, pagination *model.Pagination) *dto.PaginatedPersonsDto {
+	var nextCursor *string
+	if page.NextCursor != nil {
+		encoded := page.NextCursor.Encode()
+		nextCursor = &encoded
+	}
+
+	return &dto.PaginatedPersonsDto{
+		Data:       MapToManyPersonDto(page.Persons...),
+		Page:       pagination.Page,
+		PageSize:   pagination.PageSize,
+		Total:      page.Total,
+		TotalPages: totalPages(page.Total, pagination.PageSize),
+		NextCursor: nextCursor,
+	}
+}
+
+func MapToPersonAuditDto(model *model.PersonAudit) *dto.PersonAuditDto {
+	if model != nil {
+		return &dto.PersonAuditDto{
+			Id:        model.Id,
+			PersonId:  model.PersonId,
+			Action:    model.Action,
+			Before:    model.Before,
+			After:     model.After,
+			Actor:     model.Actor,
+			RequestId: model.RequestId,
+			CreatedAt: model.CreatedAt,
+		}
+	}
+
+	return nil
+}
+
+func MapToPaginatedPersonAuditDto(page *model.PersonAuditPage, pagination *model.Pagination) *dto.PaginatedPersonAuditDto {
+	entries := make([]dto.PersonAuditDto, len(page.Entries))
+	for i, v := range page.Entries {
+		entries[i] = *MapToPersonAuditDto(&v)
+	}
+
+	return &dto.PaginatedPersonAuditDto{
+		Data:       entries,
+		Page:       pagination.Page,
+		PageSize:   pagination.PageSize,
+		Total:      page.Total,
+		TotalPages: totalPages(page.Total, pagination.PageSize),
+	}
+}
+
+func totalPages(total int, pageSize int) int {
+	pages := total / pageSize
+	if total%pageSize != 0 {
+		pages++
+	}
+
+	return pages
+}
+
+func MapToEnrichmentStatusDto(person *model.Person, job *model.EnrichmentJob, maxAttempts int) *dto.EnrichmentStatusDto {
+	status := &dto.EnrichmentStatusDto{
+		PersonId:    person.Id,
+		Status:      person.EnrichmentStatus,
+		MaxAttempts: maxAttempts,
+	}
+
+	if job != nil {
+		status.Attempts = job.Attempts
+		status.LastError = job.LastError
+		status.UpdatedAt = &job.UpdatedAt
+		if job.State == model.EnrichmentJobPending || job.State == model.EnrichmentJobRunning {
+			status.NextAttemptAt = &job.RunAt
+		}
+	}
+
+	return status
+}
+
+func MapToEnrichmentDto(candidates []model.EnrichmentCandidate) *dto.EnrichmentDto {
+	if len(candidates) == 0 {
+		return nil
+	}
+
+	enrichment := &dto.EnrichmentDto{}
+	for _, candidate := range candidates {
+		candidateDto := dto.EnrichmentCandidateDto{
+			Value:       candidate.Value,
+			Probability: candidate.Probability,
+			SampleCount: candidate.SampleCount,
+			CountryId:   candidate.CountryId,
+		}
+
+		switch candidate.Kind {
+		case model.EnrichmentKindAge:
+			enrichment.Age = &candidateDto
+		case model.EnrichmentKindGender:
+			enrichment.Gender = &candidateDto
+		case model.EnrichmentKindNationality:
+			enrichment.Nationality = append(enrichment.Nationality, candidateDto)
+		}
+	}
+
+	return enrichment
+}
+
+func MapToUpstreamStatusDto(status model.UpstreamStatus) dto.UpstreamStatusDto {
+	return dto.UpstreamStatusDto{
+		Host:           status.Host,
+		Breaker:        status.Breaker,
+		RateLimit:      status.RateLimit,
+		QuotaLimit:     status.QuotaLimit,
+		QuotaRemaining: status.QuotaRemaining,
+		QuotaResetAt:   status.QuotaResetAt,
+		QuotaExhausted: status.QuotaExhausted,
+	}
+}
@@ -0,0 +1,137 @@
+package metrics
+
+import (
+	"database/sql"
+	"errors"
+	"net/http"
+	"time"
+
+	"github.com/ivanjabrony/personApi/internal/model"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/collectors"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+)
+
+const namespace = "personapi"
+
+// Metrics owns the Prometheus registry of the service and every collector
+// the layers report to.
+type Metrics struct {
+	registry *prometheus.Registry
+
+	httpRequests         *prometheus.CounterVec
+	httpRequestDuration  *prometheus.HistogramVec
+	httpRequestsInFlight prometheus.Gauge
+	httpRequestTimeouts  *prometheus.CounterVec
+
+	queryDuration *prometheus.HistogramVec
+
+	upstreamCalls        *prometheus.CounterVec
+	upstreamCallDuration *prometheus.HistogramVec
+}
+
+func New() *Metrics {
+	m := &Metrics{
+		registry: prometheus.NewRegistry(),
+		httpRequests: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: namespace,
+			Name:      "http_requests_total",
+			Help:      "HTTP requests handled, by route template and status.",
+		}, []string{"method", "route", "status"}),
+		httpRequestDuration: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Namespace: namespace,
+			Name:      "http_request_duration_seconds",
+			Help:      "HTTP request latency, by route template and status.",
+			Buckets:   prometheus.DefBuckets,
+		}, []string{"method", "route", "status"}),
+		httpRequestsInFlight: prometheus.NewGauge(prometheus.GaugeOpts{
+			Namespace: namespace,
+			Name:      "http_requests_in_flight",
+			Help:      "HTTP requests being handled.",
+		}),
+		httpRequestTimeouts: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: namespace,
+			Name:      "http_request_timeouts_total",
+			Help:      "HTTP requests answered with 504 by the timeout middleware.",
+		}, []string{"method", "route"}),
+		queryDuration: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Namespace: namespace,
+			Name:      "db_query_duration_seconds",
+			Help:      "Repository method latency, by outcome.",
+			Buckets:   []float64{.001, .0025, .005, .01, .025, .05, .1, .25, .5, 1, 2.5},
+		}, []string{"repository", "method", "outcome"}),
+		upstreamCalls: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: namespace,
+			Name:      "enrichment_upstream_calls_total",
+			Help:      "Calls to enrichment upstreams, retries included, by outcome.",
+		}, []string{"upstream", "outcome"}),
+		upstreamCallDuration: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Namespace: namespace,
+			Name:      "enrichment_upstream_call_duration_seconds",
+			Help:      "Latency of calls to enrichment upstreams.",
+			Buckets:   []float64{.01, .025, .05, .1, .25, .5, 1, 2.5, 5},
+		}, []string{"upstream"}),
+	}
+
+	m.registry.MustRegister(
+		collectors.NewGoCollector(),
+		collectors.NewProcessCollector(collectors.ProcessCollectorOpts{}),
+		m.httpRequests,
+		m.httpRequestDuration,
+		m.httpRequestsInFlight,
+		m.httpRequestTimeouts,
+		m.queryDuration,
+		m.upstreamCalls,
+		m.upstreamCallDuration,
+	)
+
+	return m
+}
+
+// RegisterDB exposes the pool statistics of db.
+func (m *Metrics) RegisterDB(db *sql.DB, name string) {
+	m.registry.MustRegister(collectors.NewDBStatsCollector(db, name))
+}
+
+func (m *Metrics) Handler() http.Handler {
+	return promhttp.HandlerFor(m.registry, promhttp.HandlerOpts{Registry: m.registry})
+}
+
+func (m *Metrics) RequestStarted() {
+	m.httpRequestsInFlight.Inc()
+}
+
+func (m *Metrics) RequestFinished(method string, route string, status string, duration time.Duration) {
+	m.httpRequestsInFlight.Dec()
+	m.httpRequests.WithLabelValues(method, route, status).Inc()
+	m.httpRequestDuration.WithLabelValues(method, route, status).Observe(duration.Seconds())
+}
+
+func (m *Metrics) RequestTimedOut(method string, route string) {
+	m.httpRequestTimeouts.WithLabelValues(method, route).Inc()
+}
+
+// ObserveQuery records a repository call. Missing or conflicting rows are
+// answers, not failures, so they are told apart from database errors.
+func (m *Metrics) ObserveQuery(repository string, method string, duration time.Duration, err error) {
+	outcome := "ok"
+	switch {
+	case err == nil:
+	case errors.Is(err, model.ErrNotFound), errors.Is(err, model.ErrConflict), errors.Is(err, model.ErrValidation),
+		errors.Is(err, model.ErrPreconditionFailed), errors.Is(err, model.ErrPreconditionRequired):
+		outcome = "rejected"
+	default:
+		outcome = "error"
+	}
+
+	m.queryDuration.WithLabelValues(repository, method, outcome).Observe(duration.Seconds())
+}
+
+// ObserveUpstreamCall records one attempt. A call rejected before reaching
+// the upstream has no latency worth recording.
+func (m *Metrics) ObserveUpstreamCall(upstream string, outcome string, duration time.Duration) {
+	m.upstreamCalls.WithLabelValues(upstream, outcome).Inc()
+	if duration > 0 {
+		m.upstreamCallDuration.WithLabelValues(upstream).Observe(duration.Seconds())
+	}
+}
@@ -0,0 +1,32 @@
+package model
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+)
+
+type Cursor struct {
+	Sort string `json:"sort,omitempty"`
+	Keys []any  `json:"keys,omitempty"`
+	Id   int    `json:"id"`
+}
+
+func (c *Cursor) Encode() string {
+	data, _ := json.Marshal(c)
+	return base64.RawURLEncoding.EncodeToString(data)
+}
+
+func DecodeCursor(encoded string) (*Cursor, error) {
+	data, err := base64.RawURLEncoding.DecodeString(encoded)
+	if err != nil {
+		return nil, fmt.Errorf("failed to decode cursor: %w", err)
+	}
+
+	var cursor Cursor
+	if err := json.Unmarshal(data, &cursor); err != nil {
+		return nil, fmt.Errorf("failed to parse cursor: %w", err)
+	}
+
+	return &cursor, nil
+}
@@ -0,0 +1,37 @@
+package model
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestCursorRoundTrip(t *testing.T) {
+	tests := []struct {
+		name   string
+		cursor Cursor
+	}{
+		{name: "id only", cursor: Cursor{Id: 42}},
+		{name: "with keys", cursor: Cursor{Sort: "-age,name", Keys: []any{30.0, "Ann"}, Id: 7}},
+		{name: "with null key", cursor: Cursor{Sort: "gender", Keys: []any{nil}, Id: 3}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			decoded, err := DecodeCursor(tt.cursor.Encode())
+			if err != nil {
+				t.Fatalf("DecodeCursor() error = %v", err)
+			}
+			if !reflect.DeepEqual(*decoded, tt.cursor) {
+				t.Errorf("DecodeCursor() = %+v, want %+v", *decoded, tt.cursor)
+			}
+		})
+	}
+}
+
+func TestDecodeCursorRejectsGarbage(t *testing.T) {
+	for _, encoded := range []string{"not base64!", "bm90IGpzb24"} {
+		if _, err := DecodeCursor(encoded); err == nil {
+			t.Errorf("DecodeCursor(%q) succeeded, want an error", encoded)
+		}
+	}
+}
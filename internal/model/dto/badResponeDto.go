@@ -1,5 +1,13 @@
 package dto
 
 type BadResponseDto struct {
-	Response string `json:"error" example:"Server error"`
+	Code     string          `json:"code" example:"internal_error"`
+	Response string          `json:"error" example:"Server error"`
+	Details  []FieldErrorDto `json:"details,omitempty"`
+}
+
+type FieldErrorDto struct {
+	Field string `json:"field" example:"surname"`
+	Rule  string `json:"rule" example:"personname"`
+	Param string `json:"param,omitempty" example:""`
 }
@@ -0,0 +1,13 @@
+package dto
+
+type BatchCreateResultDto struct {
+	Created int                  `json:"created" example:"2"`
+	Failed  int                  `json:"failed" example:"1"`
+	Results []BatchItemResultDto `json:"results"`
+}
+
+type BatchItemResultDto struct {
+	Index int             `json:"index" example:"0"`
+	Id    *int            `json:"id,omitempty" example:"1"`
+	Error *BadResponseDto `json:"error,omitempty"`
+}
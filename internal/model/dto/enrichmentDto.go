@@ -0,0 +1,16 @@
+package dto
+
+// EnrichmentDto shows what the lookups answered for the person's name, so the
+// inferred values can be weighed by their confidence.
+type EnrichmentDto struct {
+	Age         *EnrichmentCandidateDto  `json:"age,omitempty"`
+	Gender      *EnrichmentCandidateDto  `json:"gender,omitempty"`
+	Nationality []EnrichmentCandidateDto `json:"nationality,omitempty"`
+}
+
+type EnrichmentCandidateDto struct {
+	Value       string   `json:"value" example:"RU"`
+	Probability *float64 `json:"probability,omitempty" example:"0.42"`
+	SampleCount *int     `json:"sample_count,omitempty" example:"1532"`
+	CountryId   *string  `json:"country_id,omitempty" example:"RU"`
+}
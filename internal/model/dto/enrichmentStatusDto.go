@@ -0,0 +1,13 @@
+package dto
+
+import "time"
+
+type EnrichmentStatusDto struct {
+	PersonId      int        `json:"person_id" example:"1"`
+	Status        string     `json:"status" example:"pending" enums:"pending,complete,failed"`
+	Attempts      int        `json:"attempts" example:"2"`
+	MaxAttempts   int        `json:"max_attempts" example:"5"`
+	LastError     *string    `json:"last_error,omitempty" example:"age lookup: upstream unavailable"`
+	NextAttemptAt *time.Time `json:"next_attempt_at,omitempty" example:"2025-01-02T15:04:05Z"`
+	UpdatedAt     *time.Time `json:"updated_at,omitempty" example:"2025-01-02T15:04:05Z"`
+}
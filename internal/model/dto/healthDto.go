@@ -0,0 +1,23 @@
+package dto
+
+import "time"
+
+const (
+	HealthStatusUp       = "up"
+	HealthStatusDegraded = "degraded"
+	HealthStatusDown     = "down"
+)
+
+type HealthDto struct {
+	Status string           `json:"status" example:"up" enums:"up,degraded,down"`
+	Checks []HealthCheckDto `json:"checks,omitempty"`
+}
+
+type HealthCheckDto struct {
+	Name      string             `json:"name" example:"postgres"`
+	Status    string             `json:"status" example:"up" enums:"up,down"`
+	LatencyMs float64            `json:"latency_ms" example:"1.4"`
+	Error     *string            `json:"error,omitempty" example:"context deadline exceeded"`
+	CheckedAt time.Time          `json:"checked_at" example:"2025-01-02T15:04:05Z"`
+	Upstream  *UpstreamStatusDto `json:"upstream,omitempty"`
+}
@@ -1,7 +1,19 @@
 package dto
 
+import "strings"
+
 type NewPersonDto struct {
-	Name       string  `json:"name" example:"Ivan" binding:"required"`
-	Surname    string  `json:"surname" example:"Zabrodin" binding:"required"`
-	Patronymic *string `json:"patronymic" example:"Vladimirovich"`
+	Name       string  `json:"name" example:"Ivan" binding:"required,max=100,personname"`
+	Surname    string  `json:"surname" example:"Zabrodin" binding:"required,max=100,personname"`
+	Patronymic *string `json:"patronymic" example:"Vladimirovich" binding:"omitempty,min=1,max=100,personname"`
+
+	// CountryHint localizes the age and gender lookups.
+	CountryHint *string `json:"country_hint" example:"RU" binding:"omitempty,iso3166_1_alpha2"`
+}
+
+func (d *NewPersonDto) Normalize() {
+	d.Name = strings.TrimSpace(d.Name)
+	d.Surname = strings.TrimSpace(d.Surname)
+	d.Patronymic = trimOptional(d.Patronymic)
+	d.CountryHint = trimOptional(d.CountryHint)
 }
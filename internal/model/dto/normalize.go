@@ -0,0 +1,12 @@
+package dto
+
+import "strings"
+
+func trimOptional(value *string) *string {
+	if value == nil {
+		return nil
+	}
+
+	trimmed := strings.TrimSpace(*value)
+	return &trimmed
+}
@@ -0,0 +1,21 @@
+package dto
+
+import "github.com/ivanjabrony/personApi/internal/model"
+
+type PatchPersonDto struct {
+	Name       model.Optional[string] `json:"name" swaggertype:"string" example:"Ivan" binding:"omitempty,min=1,max=100,personname"`
+	Surname    model.Optional[string] `json:"surname" swaggertype:"string" example:"Zabrodin" binding:"omitempty,min=1,max=100,personname"`
+	Patronymic model.Optional[string] `json:"patronymic" swaggertype:"string" example:"Vladimirovich" binding:"omitempty,min=1,max=100,personname"`
+
+	Age         model.Optional[int]    `json:"age" swaggertype:"integer" example:"21" binding:"omitempty,min=0,max=150"`
+	Gender      model.Optional[string] `json:"gender" swaggertype:"string" example:"male" binding:"omitempty,oneof=male female"`
+	Nationality model.Optional[string] `json:"nationality" swaggertype:"string" example:"RU" binding:"omitempty,iso3166_1_alpha2"`
+}
+
+func (d *PatchPersonDto) Normalize() {
+	d.Name.Value = trimOptional(d.Name.Value)
+	d.Surname.Value = trimOptional(d.Surname.Value)
+	d.Patronymic.Value = trimOptional(d.Patronymic.Value)
+	d.Gender.Value = trimOptional(d.Gender.Value)
+	d.Nationality.Value = trimOptional(d.Nationality.Value)
+}
@@ -0,0 +1,25 @@
+package dto
+
+import (
+	"encoding/json"
+	"time"
+)
+
+type PersonAuditDto struct {
+	Id        int64            `json:"id" example:"1"`
+	PersonId  int              `json:"person_id" example:"1"`
+	Action    string           `json:"action" example:"update"`
+	Before    *json.RawMessage `json:"before" swaggertype:"object"`
+	After     *json.RawMessage `json:"after" swaggertype:"object"`
+	Actor     *string          `json:"actor" example:"operator@example.com"`
+	RequestId *string          `json:"request_id" example:"6f1c0c1e2a9b4d7f"`
+	CreatedAt time.Time        `json:"created_at" example:"2025-01-02T15:04:05Z"`
+}
+
+type PaginatedPersonAuditDto struct {
+	Data       []PersonAuditDto `json:"data"`
+	Total      int              `json:"total"`
+	Page       int              `json:"page"`
+	PageSize   int              `json:"page_size"`
+	TotalPages int              `json:"total_pages"`
+}
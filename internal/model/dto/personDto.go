@@ -1,5 +1,7 @@
 package dto
 
+import "time"
+
 type PersonDto struct {
 	Id         int     `json:"id" example:"1"`
 	Name       string  `json:"name" example:"Ivan"`
@@ -9,4 +11,12 @@ type PersonDto struct {
 	Age         *int    `json:"age" example:"21"`
 	Gender      *string `json:"gender" example:"male"`
 	Nationality *string `json:"nationality" example:"russian"`
+
+	Version          int        `json:"version" example:"1"`
+	DeletedAt        *time.Time `json:"deleted_at,omitempty" example:"2025-01-02T15:04:05Z"`
+	EnrichmentStatus string     `json:"enrichment_status" example:"complete" enums:"pending,complete,failed"`
+	EnrichedAt       *time.Time `json:"enriched_at,omitempty" example:"2025-01-02T15:04:05Z"`
+	CountryHint      *string    `json:"country_hint,omitempty" example:"RU"`
+
+	Enrichment *EnrichmentDto `json:"enrichment,omitempty"`
 }
@@ -0,0 +1,8 @@
+package dto
+
+import "time"
+
+type PurgeResultDto struct {
+	Purged        int64     `json:"purged" example:"42"`
+	DeletedBefore time.Time `json:"deleted_before" example:"2025-01-02T15:04:05Z"`
+}
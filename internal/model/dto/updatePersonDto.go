@@ -1,8 +1,21 @@
 package dto
 
+import "strings"
+
 type UpdatePersonDto struct {
-	Id         int     `json:"id" example:"1" binding:"required" `
-	Name       *string `json:"name" example:"Ivan" `
-	Surname    *string `json:"surname" example:"Zabrodin" `
-	Patronymic *string `json:"patronymic" example:"Vladimirovich"`
+	Name       string  `json:"name" example:"Ivan" binding:"required,max=100,personname"`
+	Surname    string  `json:"surname" example:"Zabrodin" binding:"required,max=100,personname"`
+	Patronymic *string `json:"patronymic" example:"Vladimirovich" binding:"omitempty,min=1,max=100,personname"`
+
+	Age         *int    `json:"age" example:"21" binding:"omitempty,min=0,max=150"`
+	Gender      *string `json:"gender" example:"male" binding:"omitempty,oneof=male female"`
+	Nationality *string `json:"nationality" example:"RU" binding:"omitempty,iso3166_1_alpha2"`
+}
+
+func (d *UpdatePersonDto) Normalize() {
+	d.Name = strings.TrimSpace(d.Name)
+	d.Surname = strings.TrimSpace(d.Surname)
+	d.Patronymic = trimOptional(d.Patronymic)
+	d.Gender = trimOptional(d.Gender)
+	d.Nationality = trimOptional(d.Nationality)
 }
@@ -0,0 +1,13 @@
+package dto
+
+import "time"
+
+type UpstreamStatusDto struct {
+	Host           string     `json:"host" example:"api.agify.io"`
+	Breaker        string     `json:"breaker" example:"closed" enums:"closed,open,half_open"`
+	RateLimit      *float64   `json:"rate_limit,omitempty" example:"5"`
+	QuotaLimit     *int       `json:"quota_limit,omitempty" example:"1000"`
+	QuotaRemaining *int       `json:"quota_remaining,omitempty" example:"742"`
+	QuotaResetAt   *time.Time `json:"quota_reset_at,omitempty" example:"2025-01-03T00:00:00Z"`
+	QuotaExhausted bool       `json:"quota_exhausted" example:"false"`
+}
@@ -0,0 +1,154 @@
+package model
+
+import (
+	"slices"
+	"strconv"
+)
+
+// Kind of looked up value, shared by the enrichment cache and candidates.
+const (
+	EnrichmentKindAge         = "age"
+	EnrichmentKindGender      = "gender"
+	EnrichmentKindNationality = "nationality"
+)
+
+// AgeEstimate is agify's answer, Count is the amount of samples behind it and
+// CountryId is set when the lookup was localized.
+type AgeEstimate struct {
+	Age       int    `json:"age"`
+	Count     int    `json:"count"`
+	CountryId string `json:"country_id,omitempty"`
+}
+
+type GenderEstimate struct {
+	Gender      string  `json:"gender"`
+	Probability float64 `json:"probability"`
+	Count       int     `json:"count"`
+	CountryId   string  `json:"country_id,omitempty"`
+}
+
+// NationalityEstimate lists the likely countries, most probable first.
+type NationalityEstimate struct {
+	Countries []CountryProbability `json:"countries"`
+	Count     int                  `json:"count"`
+}
+
+type CountryProbability struct {
+	CountryId   string  `json:"country_id"`
+	Probability float64 `json:"probability"`
+}
+
+func NewNationalityEstimate(countries []CountryProbability, count int) *NationalityEstimate {
+	sorted := slices.Clone(countries)
+	slices.SortStableFunc(sorted, func(a, b CountryProbability) int {
+		switch {
+		case a.Probability > b.Probability:
+			return -1
+		case a.Probability < b.Probability:
+			return 1
+		default:
+			return 0
+		}
+	})
+
+	return &NationalityEstimate{Countries: sorted, Count: count}
+}
+
+// Top returns the most probable country or nil when there is none, the
+// countries are kept sorted by NewNationalityEstimate.
+func (e *NationalityEstimate) Top() *CountryProbability {
+	if e == nil || len(e.Countries) == 0 {
+		return nil
+	}
+
+	top := e.Countries[0]
+	return &top
+}
+
+// EnrichmentCandidate is one stored answer of a lookup. Age and gender have a
+// single candidate, nationality one per country ranked by probability.
+type EnrichmentCandidate struct {
+	PersonId    int      `db:"person_id"`
+	Kind        string   `db:"kind"`
+	Rank        int      `db:"rank"`
+	Value       string   `db:"value"`
+	Probability *float64 `db:"probability"`
+	SampleCount *int     `db:"sample_count"`
+	CountryId   *string  `db:"country_id"`
+}
+
+// Progress is the enrichment as a job progress with every lookup answered.
+func (e *Enrichment) Progress() *EnrichmentProgress {
+	return &EnrichmentProgress{
+		Age:         Optional[AgeEstimate]{Set: true, Value: e.Age},
+		Gender:      Optional[GenderEstimate]{Set: true, Value: e.Gender},
+		Nationality: Optional[NationalityEstimate]{Set: true, Value: e.Nationality},
+	}
+}
+
+func (e *Enrichment) AgeValue() *int {
+	if e.Age == nil {
+		return nil
+	}
+	return &e.Age.Age
+}
+
+func (e *Enrichment) GenderValue() *string {
+	if e.Gender == nil {
+		return nil
+	}
+	return &e.Gender.Gender
+}
+
+func (e *Enrichment) NationalityValue() *string {
+	top := e.Nationality.Top()
+	if top == nil {
+		return nil
+	}
+	return &top.CountryId
+}
+
+func (e *Enrichment) Candidates(personId int) []EnrichmentCandidate {
+	var candidates []EnrichmentCandidate
+
+	if e.Age != nil {
+		candidates = append(candidates, EnrichmentCandidate{
+			PersonId:    personId,
+			Kind:        EnrichmentKindAge,
+			Value:       strconv.Itoa(e.Age.Age),
+			SampleCount: &e.Age.Count,
+			CountryId:   nilIfEmpty(e.Age.CountryId),
+		})
+	}
+	if e.Gender != nil {
+		candidates = append(candidates, EnrichmentCandidate{
+			PersonId:    personId,
+			Kind:        EnrichmentKindGender,
+			Value:       e.Gender.Gender,
+			Probability: &e.Gender.Probability,
+			SampleCount: &e.Gender.Count,
+			CountryId:   nilIfEmpty(e.Gender.CountryId),
+		})
+	}
+	if e.Nationality != nil {
+		for i := range e.Nationality.Countries {
+			candidates = append(candidates, EnrichmentCandidate{
+				PersonId:    personId,
+				Kind:        EnrichmentKindNationality,
+				Rank:        i,
+				Value:       e.Nationality.Countries[i].CountryId,
+				Probability: &e.Nationality.Countries[i].Probability,
+				SampleCount: &e.Nationality.Count,
+			})
+		}
+	}
+
+	return candidates
+}
+
+func nilIfEmpty(value string) *string {
+	if value == "" {
+		return nil
+	}
+	return &value
+}
@@ -0,0 +1,105 @@
+package model
+
+import (
+	"encoding/json"
+	"time"
+)
+
+// Enrichment status of a person, stored next to the person itself.
+const (
+	EnrichmentStatusPending  = "pending"
+	EnrichmentStatusComplete = "complete"
+	EnrichmentStatusFailed   = "failed"
+)
+
+// State of a job in the enrichment queue.
+const (
+	EnrichmentJobPending = "pending"
+	EnrichmentJobRunning = "running"
+	EnrichmentJobDone    = "done"
+	EnrichmentJobDead    = "dead"
+)
+
+type EnrichmentJob struct {
+	Id          int64     `db:"id"`
+	PersonId    int       `db:"person_id"`
+	Name        string    `db:"name"`
+	CountryHint *string   `db:"country_hint"`
+	State       string    `db:"state"`
+	Attempts    int       `db:"attempts"`
+	Overwrite   bool      `db:"overwrite"`
+	LastError   *string   `db:"last_error"`
+	RunAt       time.Time `db:"run_at"`
+	UpdatedAt   time.Time `db:"updated_at"`
+	// TraceParent is the W3C traceparent of the request that queued the job.
+	TraceParent *string `db:"trace_parent"`
+	// Generation changes with every claim and enqueue of the job.
+	Generation int64 `db:"generation"`
+	// Progress is the EnrichmentProgress earlier attempts left behind.
+	Progress *json.RawMessage `db:"progress"`
+}
+
+// DecodeProgress reads what earlier attempts of the job looked up.
+func (j *EnrichmentJob) DecodeProgress() (EnrichmentProgress, error) {
+	var progress EnrichmentProgress
+	if j.Progress == nil {
+		return progress, nil
+	}
+
+	err := json.Unmarshal(*j.Progress, &progress)
+	return progress, err
+}
+
+// Enrichment is the outcome of the three lookups of one name, a nil estimate
+// means the upstream does not know the name.
+type Enrichment struct {
+	Age         *AgeEstimate
+	Gender      *GenderEstimate
+	Nationality *NationalityEstimate
+}
+
+// EnrichmentProgress holds the lookups of a job that got an answer, a retry
+// only repeats the others. A set field with a nil value is a name the
+// upstream does not know.
+type EnrichmentProgress struct {
+	Age         Optional[AgeEstimate]         `json:"age"`
+	Gender      Optional[GenderEstimate]      `json:"gender"`
+	Nationality Optional[NationalityEstimate] `json:"nationality"`
+}
+
+// Answered lists the kinds of the lookups that got an answer.
+func (p *EnrichmentProgress) Answered() []string {
+	var kinds []string
+	if p.Age.Set {
+		kinds = append(kinds, EnrichmentKindAge)
+	}
+	if p.Gender.Set {
+		kinds = append(kinds, EnrichmentKindGender)
+	}
+	if p.Nationality.Set {
+		kinds = append(kinds, EnrichmentKindNationality)
+	}
+
+	return kinds
+}
+
+// Enrichment is the answers so far, lookups without one are nil.
+func (p *EnrichmentProgress) Enrichment() *Enrichment {
+	return &Enrichment{Age: p.Age.Value, Gender: p.Gender.Value, Nationality: p.Nationality.Value}
+}
+
+// MarshalJSON leaves lookups without an answer out, so they decode unset.
+func (p EnrichmentProgress) MarshalJSON() ([]byte, error) {
+	answers := make(map[string]any, 3)
+	if p.Age.Set {
+		answers[EnrichmentKindAge] = p.Age.Value
+	}
+	if p.Gender.Set {
+		answers[EnrichmentKindGender] = p.Gender.Value
+	}
+	if p.Nationality.Set {
+		answers[EnrichmentKindNationality] = p.Nationality.Value
+	}
+
+	return json.Marshal(answers)
+}
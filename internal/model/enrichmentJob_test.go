@@ -0,0 +1,42 @@
+package model
+
+import (
+	"encoding/json"
+	"reflect"
+	"testing"
+)
+
+func TestEnrichmentProgressRoundTrip(t *testing.T) {
+	progress := EnrichmentProgress{
+		Age:    Optional[AgeEstimate]{Set: true},
+		Gender: Optional[GenderEstimate]{Set: true, Value: &GenderEstimate{Gender: "female", Probability: 0.9, Count: 12}},
+	}
+
+	data, err := json.Marshal(progress)
+	if err != nil {
+		t.Fatalf("Marshal() error = %v", err)
+	}
+
+	raw := json.RawMessage(data)
+	got, err := (&EnrichmentJob{Progress: &raw}).DecodeProgress()
+	if err != nil {
+		t.Fatalf("DecodeProgress() error = %v", err)
+	}
+
+	if !reflect.DeepEqual(got, progress) {
+		t.Errorf("DecodeProgress() = %+v, want %+v", got, progress)
+	}
+	if kinds := got.Answered(); !reflect.DeepEqual(kinds, []string{EnrichmentKindAge, EnrichmentKindGender}) {
+		t.Errorf("Answered() = %v", kinds)
+	}
+}
+
+func TestDecodeProgressWithoutProgress(t *testing.T) {
+	got, err := (&EnrichmentJob{}).DecodeProgress()
+	if err != nil {
+		t.Fatalf("DecodeProgress() error = %v", err)
+	}
+	if len(got.Answered()) != 0 {
+		t.Errorf("Answered() = %v, want none", got.Answered())
+	}
+}
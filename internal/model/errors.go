@@ -0,0 +1,40 @@
+package model
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
+
+var (
+	ErrNotFound            = errors.New("not found")
+	ErrValidation          = errors.New("validation failed")
+	ErrConflict            = errors.New("conflict")
+	ErrUpstreamUnavailable = errors.New("upstream unavailable")
+
+	ErrPreconditionFailed   = errors.New("precondition failed")
+	ErrPreconditionRequired = errors.New("precondition required")
+)
+
+type FieldError struct {
+	Field string
+	Rule  string
+	Param string
+}
+
+type ValidationError struct {
+	Fields []FieldError
+}
+
+func (e *ValidationError) Error() string {
+	fields := make([]string, len(e.Fields))
+	for i, field := range e.Fields {
+		fields[i] = fmt.Sprintf("%s (%s)", field.Field, field.Rule)
+	}
+
+	return fmt.Sprintf("%s: %s", ErrValidation, strings.Join(fields, ", "))
+}
+
+func (e *ValidationError) Unwrap() error {
+	return ErrValidation
+}
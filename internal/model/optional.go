@@ -0,0 +1,33 @@
+package model
+
+import "encoding/json"
+
+// Optional distinguishes a field that is absent from the payload (Set is false)
+// from a field explicitly set to null (Set is true, Value is nil).
+type Optional[T any] struct {
+	Set   bool
+	Value *T
+}
+
+func (o *Optional[T]) UnmarshalJSON(data []byte) error {
+	o.Set = true
+	if string(data) == "null" {
+		o.Value = nil
+		return nil
+	}
+
+	var value T
+	if err := json.Unmarshal(data, &value); err != nil {
+		return err
+	}
+	o.Value = &value
+
+	return nil
+}
+
+func (o Optional[T]) ValidationValue() any {
+	if o.Value == nil {
+		return nil
+	}
+	return *o.Value
+}
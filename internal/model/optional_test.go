@@ -0,0 +1,68 @@
+package model
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+func TestOptionalUnmarshalJSON(t *testing.T) {
+	type payload struct {
+		Name Optional[string] `json:"name"`
+		Age  Optional[int]    `json:"age"`
+	}
+
+	tests := []struct {
+		name     string
+		body     string
+		wantSet  bool
+		wantNull bool
+		wantAge  int
+		wantErr  bool
+	}{
+		{name: "absent", body: `{}`, wantSet: false, wantNull: true},
+		{name: "null", body: `{"age": null}`, wantSet: true, wantNull: true},
+		{name: "value", body: `{"age": 42}`, wantSet: true, wantAge: 42},
+		{name: "zero value", body: `{"age": 0}`, wantSet: true, wantAge: 0},
+		{name: "wrong type", body: `{"age": "old"}`, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var got payload
+			err := json.Unmarshal([]byte(tt.body), &got)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("Unmarshal() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if tt.wantErr {
+				return
+			}
+
+			if got.Name.Set {
+				t.Errorf("Name.Set = true for a payload without name")
+			}
+			if got.Age.Set != tt.wantSet {
+				t.Errorf("Age.Set = %t, want %t", got.Age.Set, tt.wantSet)
+			}
+			if (got.Age.Value == nil) != tt.wantNull {
+				t.Fatalf("Age.Value = %v, want null %t", got.Age.Value, tt.wantNull)
+			}
+			if got.Age.Value != nil && *got.Age.Value != tt.wantAge {
+				t.Errorf("*Age.Value = %d, want %d", *got.Age.Value, tt.wantAge)
+			}
+		})
+	}
+}
+
+func TestOptionalValidationValue(t *testing.T) {
+	age := 42
+
+	if got := (Optional[int]{}).ValidationValue(); got != nil {
+		t.Errorf("absent ValidationValue() = %v, want nil", got)
+	}
+	if got := (Optional[int]{Set: true}).ValidationValue(); got != nil {
+		t.Errorf("null ValidationValue() = %v, want nil", got)
+	}
+	if got := (Optional[int]{Set: true, Value: &age}).ValidationValue(); got != 42 {
+		t.Errorf("ValidationValue() = %v, want 42", got)
+	}
+}
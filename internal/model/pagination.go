@@ -0,0 +1,18 @@
+package model
+
+type Pagination struct {
+	Page     int     `json:"page"`
+	PageSize int     `json:"page_size"`
+	Cursor   *Cursor `json:"cursor,omitempty"`
+}
+
+func (p *Pagination) Limit() int {
+	return p.PageSize
+}
+
+func (p *Pagination) Offset() int {
+	if p.Cursor != nil {
+		return 0
+	}
+	return (p.Page - 1) * p.PageSize
+}
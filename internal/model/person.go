@@ -1,5 +1,7 @@
 package model
 
+import "time"
+
 type Person struct {
 	Id         int     `json:"id"`
 	Name       string  `json:"name"`
@@ -9,4 +11,15 @@ type Person struct {
 	Age         *int    `json:"age"`
 	Gender      *string `json:"gender"`
 	Nationality *string `json:"nationality"`
+
+	Version          int        `json:"version"`
+	DeletedAt        *time.Time `json:"deleted_at,omitempty" db:"deleted_at"`
+	EnrichmentStatus string     `json:"enrichment_status" db:"enrichment_status"`
+	EnrichedAt       *time.Time `json:"enriched_at,omitempty" db:"enriched_at"`
+	CountryHint      *string    `json:"country_hint,omitempty" db:"country_hint"`
+
+	Candidates []EnrichmentCandidate `json:"-" db:"-"`
 }
+
+// AnyVersion skips the optimistic concurrency check, it is what If-Match: * maps to.
+const AnyVersion = 0
@@ -0,0 +1,32 @@
+package model
+
+import (
+	"encoding/json"
+	"time"
+)
+
+const (
+	AuditActionCreate  = "create"
+	AuditActionUpdate  = "update"
+	AuditActionPatch   = "patch"
+	AuditActionDelete  = "delete"
+	AuditActionRestore = "restore"
+	AuditActionPurge   = "purge"
+	AuditActionEnrich  = "enrich"
+)
+
+type PersonAudit struct {
+	Id        int64            `json:"id" db:"id"`
+	PersonId  int              `json:"person_id" db:"person_id"`
+	Action    string           `json:"action" db:"action"`
+	Before    *json.RawMessage `json:"before" db:"before"`
+	After     *json.RawMessage `json:"after" db:"after"`
+	Actor     *string          `json:"actor" db:"actor"`
+	RequestId *string          `json:"request_id" db:"request_id"`
+	CreatedAt time.Time        `json:"created_at" db:"created_at"`
+}
+
+type PersonAuditPage struct {
+	Entries []PersonAudit
+	Total   int
+}
@@ -12,4 +12,8 @@ type PersonFilter struct {
 	PatronymicLike *string `json:"patronymic_like"`
 	AgeMin         *int    `json:"age_min"`
 	AgeMax         *int    `json:"age_max"`
+
+	Sort []SortField `json:"sort"`
+
+	IncludeDeleted bool `json:"include_deleted"`
 }
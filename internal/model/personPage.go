@@ -0,0 +1,7 @@
+package model
+
+type PersonPage struct {
+	Persons    []Person
+	Total      int
+	NextCursor *Cursor
+}
@@ -0,0 +1,19 @@
+package model
+
+type PersonPatch struct {
+	Id         int
+	Name       Optional[string]
+	Surname    Optional[string]
+	Patronymic Optional[string]
+
+	Age         Optional[int]
+	Gender      Optional[string]
+	Nationality Optional[string]
+
+	Version int
+}
+
+func (p *PersonPatch) IsEmpty() bool {
+	return !p.Name.Set && !p.Surname.Set && !p.Patronymic.Set &&
+		!p.Age.Set && !p.Gender.Set && !p.Nationality.Set
+}
@@ -0,0 +1,71 @@
+package model
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+)
+
+var PersonSortColumns = []string{"id", "name", "surname", "patronymic", "age", "gender", "nationality"}
+
+type SortField struct {
+	Column string `json:"column"`
+	Desc   bool   `json:"desc"`
+}
+
+func ParseSort(raw string) ([]SortField, error) {
+	var fields []SortField
+
+	for _, part := range strings.Split(raw, ",") {
+		part = strings.TrimSpace(part)
+		if part == "" {
+			continue
+		}
+
+		field := SortField{Column: part}
+		if strings.HasPrefix(part, "-") {
+			field = SortField{Column: part[1:], Desc: true}
+		} else if strings.HasPrefix(part, "+") {
+			field.Column = part[1:]
+		}
+
+		if !slices.Contains(PersonSortColumns, field.Column) {
+			return nil, fmt.Errorf("unknown sort column %q", field.Column)
+		}
+		if slices.ContainsFunc(fields, func(f SortField) bool { return f.Column == field.Column }) {
+			return nil, fmt.Errorf("duplicate sort column %q", field.Column)
+		}
+
+		fields = append(fields, field)
+	}
+
+	return fields, nil
+}
+
+// EffectiveSort is the order persons are actually listed in: the given
+// fields up to and including id, with id appended as the tiebreaker when
+// missing. Cursors record it, so "name" and "name,id" are the same order.
+func EffectiveSort(fields []SortField) []SortField {
+	result := make([]SortField, 0, len(fields)+1)
+	for _, field := range fields {
+		result = append(result, field)
+		if field.Column == "id" {
+			return result
+		}
+	}
+
+	return append(result, SortField{Column: "id"})
+}
+
+func FormatSort(fields []SortField) string {
+	parts := make([]string, len(fields))
+	for i, field := range fields {
+		if field.Desc {
+			parts[i] = "-" + field.Column
+		} else {
+			parts[i] = field.Column
+		}
+	}
+
+	return strings.Join(parts, ",")
+}
@@ -0,0 +1,47 @@
+package model
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestParseSort(t *testing.T) {
+	tests := []struct {
+		name    string
+		raw     string
+		want    []SortField
+		wantErr bool
+	}{
+		{name: "empty", raw: "", want: nil},
+		{name: "ascending", raw: "name", want: []SortField{{Column: "name"}}},
+		{name: "explicit ascending", raw: "+age", want: []SortField{{Column: "age"}}},
+		{name: "descending", raw: "-age", want: []SortField{{Column: "age", Desc: true}}},
+		{name: "several with spaces", raw: " -age, surname ,", want: []SortField{{Column: "age", Desc: true}, {Column: "surname"}}},
+		{name: "unknown column", raw: "salary", wantErr: true},
+		{name: "duplicate column", raw: "age,-age", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ParseSort(tt.raw)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ParseSort(%q) error = %v, wantErr %v", tt.raw, err, tt.wantErr)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("ParseSort(%q) = %v, want %v", tt.raw, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestFormatSortRoundTrip(t *testing.T) {
+	for _, raw := range []string{"", "name", "-age,surname", "gender,-id"} {
+		fields, err := ParseSort(raw)
+		if err != nil {
+			t.Fatalf("ParseSort(%q) error = %v", raw, err)
+		}
+		if got := FormatSort(fields); got != raw {
+			t.Errorf("FormatSort(ParseSort(%q)) = %q", raw, got)
+		}
+	}
+}
@@ -0,0 +1,19 @@
+package model
+
+import "context"
+
+type RequestMeta struct {
+	RequestId string
+	Actor     string
+}
+
+type requestMetaKey struct{}
+
+func ContextWithRequestMeta(ctx context.Context, meta RequestMeta) context.Context {
+	return context.WithValue(ctx, requestMetaKey{}, meta)
+}
+
+func RequestMetaFromContext(ctx context.Context) RequestMeta {
+	meta, _ := ctx.Value(requestMetaKey{}).(RequestMeta)
+	return meta
+}
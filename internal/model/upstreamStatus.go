@@ -0,0 +1,22 @@
+package model
+
+import "time"
+
+const (
+	BreakerClosed   = "closed"
+	BreakerOpen     = "open"
+	BreakerHalfOpen = "half_open"
+)
+
+// UpstreamStatus describes how calls to one enrichment upstream are guarded.
+// RateLimit is nil when calls are not limited. Quota fields stay nil until
+// the upstream has reported its quota.
+type UpstreamStatus struct {
+	Host           string
+	Breaker        string
+	RateLimit      *float64
+	QuotaLimit     *int
+	QuotaRemaining *int
+	QuotaResetAt   *time.Time
+	QuotaExhausted bool
+}
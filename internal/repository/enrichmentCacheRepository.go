@@ -0,0 +1,11 @@
+package repository
+
+import (
+	"context"
+	"time"
+)
+
+type EnrichmentCacheRepository interface {
+	Get(context.Context, string, string) ([]byte, bool, error)
+	Set(context.Context, string, string, []byte, time.Time) error
+}
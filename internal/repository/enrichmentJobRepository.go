@@ -0,0 +1,18 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"github.com/ivanjabrony/personApi/internal/model"
+)
+
+type EnrichmentJobRepository interface {
+	Claim(context.Context, int, time.Duration) ([]model.EnrichmentJob, error)
+	Complete(context.Context, *model.EnrichmentJob, *model.Enrichment) error
+	Retry(context.Context, *model.EnrichmentJob, *model.EnrichmentProgress, time.Time, string) error
+	DeadLetter(context.Context, *model.EnrichmentJob, *model.EnrichmentProgress, string) error
+	Enqueue(context.Context, int, bool) error
+	EnqueueStale(context.Context, int, int, time.Time) ([]int, error)
+	GetByPersonId(context.Context, int) (*model.EnrichmentJob, error)
+}
@@ -0,0 +1,8 @@
+package repository
+
+import "context"
+
+type HealthRepository interface {
+	Ping(context.Context) error
+	SchemaVersion(context.Context) (uint, bool, error)
+}
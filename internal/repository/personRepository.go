@@ -2,15 +2,21 @@ package repository
 
 import (
 	"context"
+	"time"
 
 	"github.com/ivanjabrony/personApi/internal/model"
 )
 
 type PersonRepository interface {
 	Create(context.Context, *model.Person) (int, error)
-	GetById(context.Context, int) (*model.Person, error)
-	GetAll(context.Context) ([]model.Person, error)
-	GetFiltered(context.Context, *model.PersonFilter) ([]model.Person, error)
-	Update(context.Context, *model.Person) error
-	DeleteById(context.Context, int) error
+	CreateMany(context.Context, []model.Person) ([]int, error)
+	GetById(context.Context, int, bool) (*model.Person, error)
+	GetAll(context.Context, *model.Pagination) (*model.PersonPage, error)
+	GetFiltered(context.Context, *model.PersonFilter, *model.Pagination) (*model.PersonPage, error)
+	Update(context.Context, *model.Person) (*model.Person, error)
+	Patch(context.Context, *model.PersonPatch) (*model.Person, error)
+	DeleteById(context.Context, int, int) error
+	Restore(context.Context, int) (*model.Person, error)
+	PurgeDeleted(context.Context, time.Time) (int64, error)
+	GetHistory(context.Context, int, *model.Pagination) (*model.PersonAuditPage, error)
 }
@@ -0,0 +1,38 @@
+package pg
+
+import (
+	"database/sql/driver"
+	"errors"
+	"fmt"
+	"net"
+
+	"github.com/ivanjabrony/personApi/internal/model"
+	"github.com/lib/pq"
+)
+
+func translatePgError(err error) error {
+	if err == nil {
+		return nil
+	}
+
+	var pqErr *pq.Error
+	if errors.As(err, &pqErr) {
+		switch {
+		case pqErr.Code == "23505":
+			return fmt.Errorf("%w: %w", model.ErrConflict, err)
+		case pqErr.Code.Class() == "23":
+			return fmt.Errorf("%w: %w", model.ErrValidation, err)
+		case pqErr.Code.Class() == "08", pqErr.Code.Class() == "53",
+			pqErr.Code == "57P01", pqErr.Code == "57P02", pqErr.Code == "57P03":
+			return fmt.Errorf("%w: %w", model.ErrUpstreamUnavailable, err)
+		}
+		return err
+	}
+
+	var netErr net.Error
+	if errors.As(err, &netErr) || errors.Is(err, driver.ErrBadConn) {
+		return fmt.Errorf("%w: %w", model.ErrUpstreamUnavailable, err)
+	}
+
+	return err
+}
@@ -0,0 +1,67 @@
+package pg
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/Masterminds/squirrel"
+	"github.com/jmoiron/sqlx"
+)
+
+type PgEnrichmentCacheRepository struct {
+	db *sqlx.DB
+}
+
+func NewPgEnrichmentCacheRepository(db *sqlx.DB) *PgEnrichmentCacheRepository {
+	return &PgEnrichmentCacheRepository{db}
+}
+
+func (r *PgEnrichmentCacheRepository) Get(ctx context.Context, kind string, name string) ([]byte, bool, error) {
+	query, args, err := squirrel.
+		Select("value").
+		From("enrichment_cache").
+		Where(squirrel.Eq{"kind": kind, "name": name}).
+		Where("expires_at > now()").
+		PlaceholderFormat(squirrel.Dollar).
+		ToSql()
+
+	if err != nil {
+		return nil, false, fmt.Errorf("failed to build query: %w", err)
+	}
+
+	var value []byte
+
+	err = r.db.GetContext(ctx, &value, query, args...)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, false, nil
+	}
+	if err != nil {
+		return nil, false, fmt.Errorf("failed to execute query: %w", translatePgError(err))
+	}
+
+	return value, true, nil
+}
+
+func (r *PgEnrichmentCacheRepository) Set(ctx context.Context, kind string, name string, value []byte, expiresAt time.Time) error {
+	query, args, err := squirrel.
+		Insert("enrichment_cache").
+		Columns("kind", "name", "value", "expires_at").
+		Values(kind, name, value, expiresAt).
+		Suffix("ON CONFLICT (kind, name) DO UPDATE SET value = EXCLUDED.value, expires_at = EXCLUDED.expires_at").
+		PlaceholderFormat(squirrel.Dollar).
+		ToSql()
+
+	if err != nil {
+		return fmt.Errorf("failed to build query: %w", err)
+	}
+
+	_, err = r.db.ExecContext(ctx, query, args...)
+	if err != nil {
+		return fmt.Errorf("failed to execute query: %w", translatePgError(err))
+	}
+
+	return nil
+}
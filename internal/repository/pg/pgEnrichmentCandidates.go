@@ -0,0 +1,112 @@
+package pg
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/Masterminds/squirrel"
+	"github.com/ivanjabrony/personApi/internal/model"
+	"github.com/jmoiron/sqlx"
+)
+
+var enrichmentCandidateColumns = []string{"person_id", "kind", "rank", "value", "probability", "sample_count", "country_id"}
+
+// replaceCandidates swaps the stored candidates of the given kinds, or of
+// every kind when kinds is empty, for a new set. An empty set just removes
+// them.
+func replaceCandidates(ctx context.Context, tx *sqlx.Tx, personId int, kinds []string, candidates []model.EnrichmentCandidate) error {
+	deleteQuery := squirrel.
+		Delete("person_enrichment_candidates").
+		Where(squirrel.Eq{"person_id": personId})
+
+	if len(kinds) != 0 {
+		deleteQuery = deleteQuery.Where(squirrel.Eq{"kind": kinds})
+	}
+
+	query, args, err := deleteQuery.
+		PlaceholderFormat(squirrel.Dollar).
+		ToSql()
+
+	if err != nil {
+		return fmt.Errorf("failed to build query: %w", err)
+	}
+
+	_, err = tx.ExecContext(ctx, query, args...)
+	if err != nil {
+		return fmt.Errorf("failed to execute query: %w", translatePgError(err))
+	}
+
+	if len(candidates) == 0 {
+		return nil
+	}
+
+	queryString := squirrel.
+		Insert("person_enrichment_candidates").
+		Columns(enrichmentCandidateColumns...)
+
+	for _, candidate := range candidates {
+		queryString = queryString.Values(
+			candidate.PersonId,
+			candidate.Kind,
+			candidate.Rank,
+			candidate.Value,
+			candidate.Probability,
+			candidate.SampleCount,
+			candidate.CountryId)
+	}
+
+	query, args, err = queryString.
+		PlaceholderFormat(squirrel.Dollar).
+		ToSql()
+
+	if err != nil {
+		return fmt.Errorf("failed to build query: %w", err)
+	}
+
+	_, err = tx.ExecContext(ctx, query, args...)
+	if err != nil {
+		return fmt.Errorf("failed to execute query: %w", translatePgError(err))
+	}
+
+	return nil
+}
+
+// attachCandidates loads the candidates of the given persons in one query.
+func attachCandidates(ctx context.Context, tx *sqlx.Tx, persons ...*model.Person) error {
+	if len(persons) == 0 {
+		return nil
+	}
+
+	byId := make(map[int]*model.Person, len(persons))
+	ids := make([]int, 0, len(persons))
+	for _, person := range persons {
+		byId[person.Id] = person
+		ids = append(ids, person.Id)
+	}
+
+	query, args, err := squirrel.
+		Select(enrichmentCandidateColumns...).
+		From("person_enrichment_candidates").
+		Where(squirrel.Eq{"person_id": ids}).
+		OrderBy("person_id", "kind", "rank").
+		PlaceholderFormat(squirrel.Dollar).
+		ToSql()
+
+	if err != nil {
+		return fmt.Errorf("failed to build query: %w", err)
+	}
+
+	var candidates []model.EnrichmentCandidate
+
+	err = tx.SelectContext(ctx, &candidates, query, args...)
+	if err != nil {
+		return fmt.Errorf("failed to execute query: %w", translatePgError(err))
+	}
+
+	for _, candidate := range candidates {
+		person := byId[candidate.PersonId]
+		person.Candidates = append(person.Candidates, candidate)
+	}
+
+	return nil
+}
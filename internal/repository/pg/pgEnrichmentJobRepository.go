@@ -0,0 +1,487 @@
+package pg
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/Masterminds/squirrel"
+	"github.com/ivanjabrony/personApi/internal/model"
+	"github.com/ivanjabrony/personApi/internal/tracing"
+	"github.com/jmoiron/sqlx"
+)
+
+var enrichmentJobColumns = []string{"id", "person_id", "state", "attempts", "overwrite", "last_error", "run_at", "updated_at", "trace_parent", "generation", "progress"}
+
+type PgEnrichmentJobRepository struct {
+	db *sqlx.DB
+}
+
+func NewPgEnrichmentJobRepository(db *sqlx.DB) *PgEnrichmentJobRepository {
+	return &PgEnrichmentJobRepository{db}
+}
+
+// Claim leases up to limit runnable jobs. A running job whose lease has
+// expired is runnable again, so a crashed worker never strands a job. Jobs
+// of deleted persons wait until the person is restored or purged.
+func (r *PgEnrichmentJobRepository) Claim(ctx context.Context, limit int, lease time.Duration) ([]model.EnrichmentJob, error) {
+	query := `
+WITH claimed AS (
+  SELECT id FROM enrichment_jobs j
+  WHERE state IN ('pending', 'running') AND run_at <= now()
+    AND EXISTS (SELECT 1 FROM persons p WHERE p.id = j.person_id AND p.deleted_at IS NULL)
+  ORDER BY run_at
+  LIMIT $1
+  FOR UPDATE SKIP LOCKED
+)
+UPDATE enrichment_jobs j
+SET state = 'running', attempts = j.attempts + 1, generation = j.generation + 1, run_at = now() + make_interval(secs => $2), updated_at = now()
+FROM claimed, persons p
+WHERE j.id = claimed.id AND p.id = j.person_id
+RETURNING j.id, j.person_id, p.name, p.country_hint, j.state, j.attempts, j.overwrite, j.last_error, j.run_at, j.updated_at, j.trace_parent, j.generation, j.progress`
+
+	var jobs []model.EnrichmentJob
+
+	err := r.db.SelectContext(ctx, &jobs, query, limit, lease.Seconds())
+	if err != nil {
+		return nil, fmt.Errorf("failed to execute query: %w", translatePgError(err))
+	}
+
+	return jobs, nil
+}
+
+// Complete closes the job and stores the looked up values together with every
+// candidate behind them, see storeEnrichment.
+func (r *PgEnrichmentJobRepository) Complete(ctx context.Context, job *model.EnrichmentJob, enrichment *model.Enrichment) error {
+	tx, err := r.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return translatePgError(err)
+	}
+
+	defer func() {
+		var e error
+		if err == nil {
+			e = tx.Commit()
+		} else {
+			e = tx.Rollback()
+		}
+
+		if err == nil && e != nil {
+			err = fmt.Errorf("finishing transaction: %w", e)
+		}
+	}()
+
+	err = finishJob(ctx, tx, job, model.EnrichmentJobDone, nil, job.RunAt, nil)
+	if err != nil {
+		return err
+	}
+
+	err = storeEnrichment(ctx, tx, job, enrichment.Progress(), model.EnrichmentStatusComplete)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// Retry reschedules the job to repeat the lookups progress has no answer
+// for. The answered ones are stored on the person right away.
+func (r *PgEnrichmentJobRepository) Retry(ctx context.Context, job *model.EnrichmentJob, progress *model.EnrichmentProgress, runAt time.Time, lastError string) error {
+	tx, err := r.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return translatePgError(err)
+	}
+
+	defer func() {
+		var e error
+		if err == nil {
+			e = tx.Commit()
+		} else {
+			e = tx.Rollback()
+		}
+
+		if err == nil && e != nil {
+			err = fmt.Errorf("finishing transaction: %w", e)
+		}
+	}()
+
+	data, err := marshalProgress(progress)
+	if err != nil {
+		return err
+	}
+
+	err = finishJob(ctx, tx, job, model.EnrichmentJobPending, &lastError, runAt, data)
+	if err != nil {
+		return err
+	}
+
+	if len(progress.Answered()) == 0 {
+		return nil
+	}
+
+	err = storeEnrichment(ctx, tx, job, progress, model.EnrichmentStatusPending)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// DeadLetter gives up on the job, stores the lookups progress has an answer
+// for and marks the person as failed. The job stays in the table for
+// inspection until the person is enriched again.
+func (r *PgEnrichmentJobRepository) DeadLetter(ctx context.Context, job *model.EnrichmentJob, progress *model.EnrichmentProgress, lastError string) error {
+	tx, err := r.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return translatePgError(err)
+	}
+
+	defer func() {
+		var e error
+		if err == nil {
+			e = tx.Commit()
+		} else {
+			e = tx.Rollback()
+		}
+
+		if err == nil && e != nil {
+			err = fmt.Errorf("finishing transaction: %w", e)
+		}
+	}()
+
+	data, err := marshalProgress(progress)
+	if err != nil {
+		return err
+	}
+
+	err = finishJob(ctx, tx, job, model.EnrichmentJobDead, &lastError, job.RunAt, data)
+	if err != nil {
+		return err
+	}
+
+	err = storeEnrichment(ctx, tx, job, progress, model.EnrichmentStatusFailed)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// Enqueue schedules a live person for enrichment, replacing a queued or
+// finished job of the same person.
+func (r *PgEnrichmentJobRepository) Enqueue(ctx context.Context, personId int, overwrite bool) error {
+	tx, err := r.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return translatePgError(err)
+	}
+
+	defer func() {
+		var e error
+		if err == nil {
+			e = tx.Commit()
+		} else {
+			e = tx.Rollback()
+		}
+
+		if err == nil && e != nil {
+			err = fmt.Errorf("finishing transaction: %w", e)
+		}
+	}()
+
+	person, err := lockPerson(ctx, tx, personId)
+	if err != nil {
+		return err
+	}
+
+	if person.DeletedAt != nil {
+		err = fmt.Errorf("person with id %d: %w", personId, model.ErrNotFound)
+		return err
+	}
+
+	err = enqueueEnrichment(ctx, tx, overwrite, personId)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// EnqueueStale schedules up to limit live persons with id above afterId whose
+// enrichment never succeeded or happened before staleBefore, skipping persons
+// that already have a job in flight. Persons with an outdated enrichment are
+// queued to overwrite it. It returns the queued ids in ascending order.
+func (r *PgEnrichmentJobRepository) EnqueueStale(ctx context.Context, afterId int, limit int, staleBefore time.Time) ([]int, error) {
+	tx, err := r.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return nil, translatePgError(err)
+	}
+
+	defer func() {
+		var e error
+		if err == nil {
+			e = tx.Commit()
+		} else {
+			e = tx.Rollback()
+		}
+
+		if err == nil && e != nil {
+			err = fmt.Errorf("finishing transaction: %w", e)
+		}
+	}()
+
+	query, args, err := squirrel.
+		Select("p.id", "p.enriched_at IS NOT NULL AS overwrite").
+		From("persons p").
+		Where(squirrel.Gt{"p.id": afterId}).
+		Where(squirrel.Eq{"p.deleted_at": nil}).
+		Where(squirrel.Or{
+			squirrel.Eq{"p.enriched_at": nil},
+			squirrel.Lt{"p.enriched_at": staleBefore},
+		}).
+		Where("NOT EXISTS (SELECT 1 FROM enrichment_jobs j WHERE j.person_id = p.id AND j.state IN ('pending', 'running'))").
+		OrderBy("p.id").
+		Limit(uint64(limit)).
+		Suffix("FOR UPDATE OF p SKIP LOCKED").
+		PlaceholderFormat(squirrel.Dollar).
+		ToSql()
+
+	if err != nil {
+		return nil, fmt.Errorf("failed to build query: %w", err)
+	}
+
+	var stale []struct {
+		Id        int  `db:"id"`
+		Overwrite bool `db:"overwrite"`
+	}
+
+	err = tx.SelectContext(ctx, &stale, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to execute query: %w", translatePgError(err))
+	}
+
+	ids := make([]int, len(stale))
+	var missing, outdated []int
+	for i, person := range stale {
+		ids[i] = person.Id
+		if person.Overwrite {
+			outdated = append(outdated, person.Id)
+		} else {
+			missing = append(missing, person.Id)
+		}
+	}
+
+	if len(missing) != 0 {
+		err = enqueueEnrichment(ctx, tx, false, missing...)
+		if err != nil {
+			return nil, err
+		}
+	}
+	if len(outdated) != 0 {
+		err = enqueueEnrichment(ctx, tx, true, outdated...)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return ids, nil
+}
+
+func (r *PgEnrichmentJobRepository) GetByPersonId(ctx context.Context, personId int) (*model.EnrichmentJob, error) {
+	query, args, err := squirrel.
+		Select(enrichmentJobColumns...).
+		From("enrichment_jobs").
+		Where(squirrel.Eq{"person_id": personId}).
+		PlaceholderFormat(squirrel.Dollar).
+		ToSql()
+
+	if err != nil {
+		return nil, fmt.Errorf("failed to build query: %w", err)
+	}
+
+	var job model.EnrichmentJob
+
+	err = r.db.GetContext(ctx, &job, query, args...)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, fmt.Errorf("enrichment job for person with id %d: %w", personId, model.ErrNotFound)
+		}
+		return nil, fmt.Errorf("failed to execute query: %w", translatePgError(err))
+	}
+
+	return &job, nil
+}
+
+// storeEnrichment writes the answered lookups of progress onto the person
+// together with every candidate behind them and sets its enrichment status.
+// Unless the job was queued to overwrite them, only empty fields are filled,
+// so values a client set while the job was queued are kept. A person deleted
+// in the meantime is left as it is, a person renamed since the claim is not
+// given values looked up for the old name.
+func storeEnrichment(ctx context.Context, tx *sqlx.Tx, job *model.EnrichmentJob, progress *model.EnrichmentProgress, status string) error {
+	before, err := lockPerson(ctx, tx, job.PersonId)
+	if err != nil {
+		return err
+	}
+
+	if before.DeletedAt != nil {
+		return nil
+	}
+
+	if !strings.EqualFold(before.Name, job.Name) {
+		return fmt.Errorf("enrichment job %d looked up %q, person is named %q now: %w", job.Id, job.Name, before.Name, model.ErrConflict)
+	}
+
+	queryString := squirrel.
+		Update("persons").
+		Set("enrichment_status", status)
+
+	if status == model.EnrichmentStatusComplete {
+		queryString = queryString.Set("enriched_at", squirrel.Expr("now()"))
+	}
+
+	enrichment := progress.Enrichment()
+	if progress.Age.Set {
+		queryString = queryString.Set("age", enrichedValue("age", enrichment.AgeValue(), job.Overwrite))
+	}
+	if progress.Gender.Set {
+		queryString = queryString.Set("gender", enrichedValue("gender", enrichment.GenderValue(), job.Overwrite))
+	}
+	if progress.Nationality.Set {
+		queryString = queryString.Set("nationality", enrichedValue("nationality", enrichment.NationalityValue(), job.Overwrite))
+	}
+
+	after, err := execUpdatePerson(ctx, tx, queryString, job.PersonId)
+	if err != nil {
+		return err
+	}
+
+	err = replaceCandidates(ctx, tx, job.PersonId, progress.Answered(), enrichment.Candidates(job.PersonId))
+	if err != nil {
+		return err
+	}
+
+	err = insertAudit(ctx, tx, model.AuditActionEnrich, job.PersonId, before, after)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func marshalProgress(progress *model.EnrichmentProgress) (*string, error) {
+	data, err := json.Marshal(progress)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal enrichment progress: %w", err)
+	}
+
+	encoded := string(data)
+	return &encoded, nil
+}
+
+// enrichedValue is the value a lookup stores into column, it only fills an
+// empty column unless the job overwrites.
+func enrichedValue(column string, value any, overwrite bool) any {
+	if overwrite {
+		return value
+	}
+	return squirrel.Expr("COALESCE("+column+", ?)", value)
+}
+
+// finishJob moves a claimed job to its next state. The generation, bumped by
+// every claim and enqueue, acts as a fencing token: a worker whose lease was
+// taken over or whose job was queued again updates nothing. progress is kept
+// for the next attempt, nil clears it.
+func finishJob(ctx context.Context, tx *sqlx.Tx, job *model.EnrichmentJob, state string, lastError *string, runAt time.Time, progress *string) error {
+	query, args, err := squirrel.
+		Update("enrichment_jobs").
+		Set("state", state).
+		Set("last_error", lastError).
+		Set("run_at", runAt).
+		Set("progress", progress).
+		Set("updated_at", squirrel.Expr("now()")).
+		Where(squirrel.Eq{"id": job.Id, "state": model.EnrichmentJobRunning, "generation": job.Generation}).
+		PlaceholderFormat(squirrel.Dollar).
+		ToSql()
+
+	if err != nil {
+		return fmt.Errorf("failed to build query: %w", err)
+	}
+
+	result, err := tx.ExecContext(ctx, query, args...)
+	if err != nil {
+		return fmt.Errorf("failed to execute query: %w", translatePgError(err))
+	}
+
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to execute query: %w", translatePgError(err))
+	}
+	if affected == 0 {
+		return fmt.Errorf("enrichment job %d lost its lease of generation %d: %w", job.Id, job.Generation, model.ErrConflict)
+	}
+
+	return nil
+}
+
+// enqueueEnrichment schedules persons for enrichment and marks them pending,
+// resetting an existing job of the same person instead of adding a second one.
+func enqueueEnrichment(ctx context.Context, tx *sqlx.Tx, overwrite bool, personIds ...int) error {
+	queryString := squirrel.
+		Insert("enrichment_jobs").
+		Columns("person_id", "overwrite", "trace_parent")
+
+	traceParent := tracing.TraceParent(ctx)
+	for _, id := range personIds {
+		queryString = queryString.Values(id, overwrite, traceParent)
+	}
+
+	query, args, err := queryString.
+		Suffix("ON CONFLICT (person_id) DO UPDATE SET state = 'pending', attempts = 0, generation = enrichment_jobs.generation + 1, progress = NULL, overwrite = EXCLUDED.overwrite, trace_parent = EXCLUDED.trace_parent, last_error = NULL, run_at = now(), updated_at = now()").
+		PlaceholderFormat(squirrel.Dollar).
+		ToSql()
+
+	if err != nil {
+		return fmt.Errorf("failed to build query: %w", err)
+	}
+
+	_, err = tx.ExecContext(ctx, query, args...)
+	if err != nil {
+		return fmt.Errorf("failed to execute query: %w", translatePgError(err))
+	}
+
+	query, args, err = squirrel.
+		Update("persons").
+		Set("enrichment_status", model.EnrichmentStatusPending).
+		Where(squirrel.Eq{"id": personIds}).
+		Where(squirrel.NotEq{"enrichment_status": model.EnrichmentStatusPending}).
+		PlaceholderFormat(squirrel.Dollar).
+		ToSql()
+
+	if err != nil {
+		return fmt.Errorf("failed to build query: %w", err)
+	}
+
+	_, err = tx.ExecContext(ctx, query, args...)
+	if err != nil {
+		return fmt.Errorf("failed to execute query: %w", translatePgError(err))
+	}
+
+	return nil
+}
+
+// clearOnRename evaluates to NULL when the update gives the person another
+// name and to value otherwise, a looked up value describes the previous name.
+// value has to carry its type, e.g. the column itself or a cast parameter.
+func clearOnRename(value squirrel.Sqlizer, name string) squirrel.Sqlizer {
+	return squirrel.Expr("CASE WHEN lower(name) <> lower(?) THEN NULL ELSE ? END", name, value)
+}
+
+// pendingOnRename marks the person pending when the update renames it, the
+// queued re-enrichment completes it again.
+func pendingOnRename(name string) squirrel.Sqlizer {
+	return squirrel.Expr("CASE WHEN lower(name) <> lower(?) THEN ? ELSE enrichment_status END", name, model.EnrichmentStatusPending)
+}
@@ -0,0 +1,45 @@
+package pg
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+
+	"github.com/jmoiron/sqlx"
+)
+
+type PgHealthRepository struct {
+	db *sqlx.DB
+}
+
+func NewPgHealthRepository(db *sqlx.DB) *PgHealthRepository {
+	return &PgHealthRepository{db}
+}
+
+func (r *PgHealthRepository) Ping(ctx context.Context) error {
+	if err := r.db.PingContext(ctx); err != nil {
+		return fmt.Errorf("failed to ping database: %w", translatePgError(err))
+	}
+
+	return nil
+}
+
+// SchemaVersion reads the version golang-migrate has recorded, zero when no
+// migration has been applied yet.
+func (r *PgHealthRepository) SchemaVersion(ctx context.Context) (uint, bool, error) {
+	var row struct {
+		Version uint `db:"version"`
+		Dirty   bool `db:"dirty"`
+	}
+
+	err := r.db.GetContext(ctx, &row, "SELECT version, dirty FROM schema_migrations LIMIT 1")
+	if errors.Is(err, sql.ErrNoRows) {
+		return 0, false, nil
+	}
+	if err != nil {
+		return 0, false, fmt.Errorf("failed to execute query: %w", translatePgError(err))
+	}
+
+	return row.Version, row.Dirty, nil
+}
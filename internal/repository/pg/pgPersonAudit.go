@@ -0,0 +1,126 @@
+package pg
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"github.com/Masterminds/squirrel"
+	"github.com/ivanjabrony/personApi/internal/model"
+	"github.com/jmoiron/sqlx"
+)
+
+var personAuditColumns = []string{"id", "person_id", "action", "before", "after", "actor", "request_id", "created_at"}
+
+func (r *PgPersonRepository) GetHistory(ctx context.Context, personId int, pagination *model.Pagination) (*model.PersonAuditPage, error) {
+	tx, err := r.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return nil, translatePgError(err)
+	}
+
+	defer func() {
+		var e error
+		if err == nil {
+			e = tx.Commit()
+		} else {
+			e = tx.Rollback()
+		}
+
+		if err == nil && e != nil {
+			err = fmt.Errorf("finishing transaction: %w", e)
+		}
+	}()
+
+	countQuery, args, err := squirrel.
+		Select("COUNT(*)").
+		From("person_audit").
+		Where(squirrel.Eq{"person_id": personId}).
+		PlaceholderFormat(squirrel.Dollar).
+		ToSql()
+
+	if err != nil {
+		return nil, fmt.Errorf("failed to build count query: %w", err)
+	}
+
+	var total int
+
+	err = tx.GetContext(ctx, &total, countQuery, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to execute count query: %w", translatePgError(err))
+	}
+
+	query, args, err := squirrel.
+		Select(personAuditColumns...).
+		From("person_audit").
+		Where(squirrel.Eq{"person_id": personId}).
+		OrderBy("id DESC").
+		Limit(uint64(pagination.Limit())).
+		Offset(uint64(pagination.Offset())).
+		PlaceholderFormat(squirrel.Dollar).
+		ToSql()
+
+	if err != nil {
+		return nil, fmt.Errorf("failed to build query: %w", err)
+	}
+
+	entries := make([]model.PersonAudit, 0, pagination.Limit())
+
+	err = tx.SelectContext(ctx, &entries, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to execute query: %w", translatePgError(err))
+	}
+
+	return &model.PersonAuditPage{Entries: entries, Total: total}, nil
+}
+
+func insertAudit(ctx context.Context, tx *sqlx.Tx, action string, personId int, before *model.Person, after *model.Person) error {
+	beforeJson, err := marshalAuditSnapshot(before)
+	if err != nil {
+		return err
+	}
+	afterJson, err := marshalAuditSnapshot(after)
+	if err != nil {
+		return err
+	}
+
+	meta := model.RequestMetaFromContext(ctx)
+
+	query, args, err := squirrel.
+		Insert("person_audit").
+		Columns("person_id", "action", "before", "after", "actor", "request_id").
+		Values(personId, action, beforeJson, afterJson, nullIfEmpty(meta.Actor), nullIfEmpty(meta.RequestId)).
+		PlaceholderFormat(squirrel.Dollar).
+		ToSql()
+
+	if err != nil {
+		return fmt.Errorf("failed to build audit query: %w", err)
+	}
+
+	_, err = tx.ExecContext(ctx, query, args...)
+	if err != nil {
+		return fmt.Errorf("failed to write audit: %w", translatePgError(err))
+	}
+
+	return nil
+}
+
+func marshalAuditSnapshot(person *model.Person) (*string, error) {
+	if person == nil {
+		return nil, nil
+	}
+
+	data, err := json.Marshal(person)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal audit snapshot: %w", err)
+	}
+
+	snapshot := string(data)
+	return &snapshot, nil
+}
+
+func nullIfEmpty(value string) *string {
+	if value == "" {
+		return nil
+	}
+	return &value
+}
@@ -5,12 +5,17 @@ import (
 	"database/sql"
 	"errors"
 	"fmt"
+	"slices"
+	"strings"
+	"time"
 
 	"github.com/Masterminds/squirrel"
 	"github.com/ivanjabrony/personApi/internal/model"
 	"github.com/jmoiron/sqlx"
 )
 
+var personColumns = []string{"id", "name", "surname", "patronymic", "age", "gender", "nationality", "version", "deleted_at", "enrichment_status", "enriched_at", "country_hint"}
+
 type PgPersonRepository struct {
 	db *sqlx.DB
 }
@@ -22,7 +27,7 @@ func NewPgPersonRepository(db *sqlx.DB) *PgPersonRepository {
 func (r *PgPersonRepository) Create(ctx context.Context, person *model.Person) (int, error) {
 	tx, err := r.db.BeginTxx(ctx, nil)
 	if err != nil {
-		return -1, err
+		return -1, translatePgError(err)
 	}
 
 	defer func() {
@@ -40,37 +45,51 @@ func (r *PgPersonRepository) Create(ctx context.Context, person *model.Person) (
 
 	query, args, err := squirrel.
 		Insert("persons").
-		Columns("name", "surname", "patronymic", "age", "gender", "nationality").
+		Columns("name", "surname", "patronymic", "age", "gender", "nationality", "enrichment_status", "country_hint").
 		Values(
 			person.Name,
 			person.Surname,
 			person.Patronymic,
 			person.Age,
 			person.Gender,
-			person.Nationality).
+			person.Nationality,
+			person.EnrichmentStatus,
+			person.CountryHint).
 		PlaceholderFormat(squirrel.Dollar).
-		Suffix("RETURNING id").
+		Suffix("RETURNING id, version").
 		ToSql()
 
 	if err != nil {
 		return -1, fmt.Errorf("failed to build query: %w", err)
 	}
 
-	err = tx.QueryRowxContext(ctx, query, args...).Scan(&person.Id)
+	err = tx.QueryRowxContext(ctx, query, args...).Scan(&person.Id, &person.Version)
 	if err != nil {
 		if errors.Is(err, sql.ErrNoRows) {
 			return -1, fmt.Errorf("person not inserted: %w", err)
 		}
-		return -1, fmt.Errorf("failed to execute query: %w", err)
+		return -1, fmt.Errorf("failed to execute query: %w", translatePgError(err))
+	}
+
+	err = insertAudit(ctx, tx, model.AuditActionCreate, person.Id, nil, person)
+	if err != nil {
+		return -1, err
+	}
+
+	if person.EnrichmentStatus == model.EnrichmentStatusPending {
+		err = enqueueEnrichment(ctx, tx, false, person.Id)
+		if err != nil {
+			return -1, err
+		}
 	}
 
 	return person.Id, nil
 }
 
-func (r *PgPersonRepository) GetById(ctx context.Context, id int) (*model.Person, error) {
+func (r *PgPersonRepository) CreateMany(ctx context.Context, persons []model.Person) ([]int, error) {
 	tx, err := r.db.BeginTxx(ctx, nil)
 	if err != nil {
-		return nil, err
+		return nil, translatePgError(err)
 	}
 
 	defer func() {
@@ -86,43 +105,78 @@ func (r *PgPersonRepository) GetById(ctx context.Context, id int) (*model.Person
 		}
 	}()
 
-	query, args, err := squirrel.
-		Select("id, name", "surname", "patronymic", "age", "gender", "nationality").
-		From("persons").
-		Where(squirrel.Eq{"id": id}).
+	queryString := squirrel.
+		Insert("persons").
+		Columns("name", "surname", "patronymic", "age", "gender", "nationality", "enrichment_status", "country_hint")
+
+	for _, person := range persons {
+		queryString = queryString.Values(
+			person.Name,
+			person.Surname,
+			person.Patronymic,
+			person.Age,
+			person.Gender,
+			person.Nationality,
+			person.EnrichmentStatus,
+			person.CountryHint)
+	}
+
+	query, args, err := queryString.
 		PlaceholderFormat(squirrel.Dollar).
+		Suffix("RETURNING id, version").
 		ToSql()
 
 	if err != nil {
 		return nil, fmt.Errorf("failed to build query: %w", err)
 	}
 
-	var person model.Person
+	rows, err := tx.QueryxContext(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to execute query: %w", translatePgError(err))
+	}
 
-	err = tx.QueryRowxContext(ctx, query, args...).Scan(
-		&person.Id,
-		&person.Name,
-		&person.Surname,
-		&person.Patronymic,
-		&person.Age,
-		&person.Gender,
-		&person.Nationality,
-	)
+	ids := make([]int, 0, len(persons))
+	for i := 0; rows.Next(); i++ {
+		err = rows.Scan(&persons[i].Id, &persons[i].Version)
+		if err != nil {
+			rows.Close()
+			return nil, fmt.Errorf("failed to scan inserted person: %w", err)
+		}
+		ids = append(ids, persons[i].Id)
+	}
+	rows.Close()
 
+	err = rows.Err()
 	if err != nil {
-		if errors.Is(err, sql.ErrNoRows) {
-			return nil, fmt.Errorf("person not inserted: %w", err)
+		return nil, fmt.Errorf("failed to execute query: %w", translatePgError(err))
+	}
+
+	pending := make([]int, 0, len(persons))
+	for i := range persons {
+		err = insertAudit(ctx, tx, model.AuditActionCreate, persons[i].Id, nil, &persons[i])
+		if err != nil {
+			return nil, err
+		}
+
+		if persons[i].EnrichmentStatus == model.EnrichmentStatusPending {
+			pending = append(pending, persons[i].Id)
 		}
-		return nil, fmt.Errorf("failed to execute query: %w", err)
 	}
 
-	return &person, nil
+	if len(pending) != 0 {
+		err = enqueueEnrichment(ctx, tx, false, pending...)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return ids, nil
 }
 
-func (r *PgPersonRepository) GetFiltered(ctx context.Context, filter *model.PersonFilter) ([]model.Person, error) {
+func (r *PgPersonRepository) GetById(ctx context.Context, id int, includeDeleted bool) (*model.Person, error) {
 	tx, err := r.db.BeginTxx(ctx, nil)
 	if err != nil {
-		return nil, err
+		return nil, translatePgError(err)
 	}
 
 	defer func() {
@@ -139,40 +193,12 @@ func (r *PgPersonRepository) GetFiltered(ctx context.Context, filter *model.Pers
 	}()
 
 	queryString := squirrel.
-		Select("id, name", "surname", "patronymic", "age", "gender", "nationality").
-		From("persons")
-
-	if filter.Name != nil {
-		queryString = queryString.Where(squirrel.Eq{"name": *filter.Name})
-	}
-	if filter.Surname != nil {
-		queryString = queryString.Where(squirrel.Eq{"surname": *filter.Surname})
-	}
-	if filter.Patronymic != nil {
-		queryString = queryString.Where(squirrel.Eq{"patronymic": *filter.Patronymic})
-	}
-	if len(filter.Nationalities) != 0 {
-		queryString = queryString.Where(squirrel.Eq{"nationality": filter.Nationalities})
-	}
-	if len(filter.Genders) != 0 {
-		queryString = queryString.Where(squirrel.Eq{"gender": filter.Genders})
-	}
-
-	if filter.NameLike != nil {
-		queryString = queryString.Where(squirrel.Like{"name": "%" + *filter.NameLike + "%"})
-	}
-	if filter.SurnameLike != nil {
-		queryString = queryString.Where(squirrel.Like{"surname": "%" + *filter.SurnameLike + "%"})
-	}
-	if filter.PatronymicLike != nil {
-		queryString = queryString.Where(squirrel.Like{"patronymic": "%" + *filter.PatronymicLike + "%"})
-	}
+		Select(personColumns...).
+		From("persons").
+		Where(squirrel.Eq{"id": id})
 
-	if filter.AgeMax != nil {
-		queryString = queryString.Where(squirrel.LtOrEq{"age": *filter.AgeMax})
-	}
-	if filter.AgeMin != nil {
-		queryString = queryString.Where(squirrel.GtOrEq{"age": *filter.AgeMin})
+	if !includeDeleted {
+		queryString = queryString.Where(squirrel.Eq{"deleted_at": nil})
 	}
 
 	query, args, err := queryString.
@@ -183,20 +209,29 @@ func (r *PgPersonRepository) GetFiltered(ctx context.Context, filter *model.Pers
 		return nil, fmt.Errorf("failed to build query: %w", err)
 	}
 
-	var persons []model.Person
+	var person model.Person
+
+	err = tx.QueryRowxContext(ctx, query, args...).StructScan(&person)
 
-	err = tx.SelectContext(ctx, &persons, query, args...)
 	if err != nil {
-		return nil, fmt.Errorf("failed to execute query: %w", err)
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, fmt.Errorf("person with id %d: %w", id, model.ErrNotFound)
+		}
+		return nil, fmt.Errorf("failed to execute query: %w", translatePgError(err))
+	}
+
+	err = attachCandidates(ctx, tx, &person)
+	if err != nil {
+		return nil, err
 	}
 
-	return persons, nil
+	return &person, nil
 }
 
-func (r *PgPersonRepository) GetAll(ctx context.Context) ([]model.Person, error) {
+func (r *PgPersonRepository) GetFiltered(ctx context.Context, filter *model.PersonFilter, pagination *model.Pagination) (*model.PersonPage, error) {
 	tx, err := r.db.BeginTxx(ctx, nil)
 	if err != nil {
-		return nil, err
+		return nil, translatePgError(err)
 	}
 
 	defer func() {
@@ -212,31 +247,45 @@ func (r *PgPersonRepository) GetAll(ctx context.Context) ([]model.Person, error)
 		}
 	}()
 
-	query, args, err := squirrel.
-		Select("id, name", "surname", "patronymic", "age", "gender", "nationality").
-		From("persons").
-		PlaceholderFormat(squirrel.Dollar).
-		ToSql()
+	page, err := selectPersonsPage(ctx, tx, filter, pagination)
+	if err != nil {
+		return nil, err
+	}
 
+	return page, nil
+}
+
+func (r *PgPersonRepository) GetAll(ctx context.Context, pagination *model.Pagination) (*model.PersonPage, error) {
+	tx, err := r.db.BeginTxx(ctx, nil)
 	if err != nil {
-		return nil, fmt.Errorf("failed to build query: %w", err)
+		return nil, translatePgError(err)
 	}
 
-	var persons []model.Person
+	defer func() {
+		var e error
+		if err == nil {
+			e = tx.Commit()
+		} else {
+			e = tx.Rollback()
+		}
 
-	err = tx.SelectContext(ctx, &persons, query, args...)
+		if err == nil && e != nil {
+			err = fmt.Errorf("finishing transaction: %w", e)
+		}
+	}()
 
+	page, err := selectPersonsPage(ctx, tx, nil, pagination)
 	if err != nil {
-		return nil, fmt.Errorf("failed to execute query: %w", err)
+		return nil, err
 	}
 
-	return persons, nil
+	return page, nil
 }
 
-func (r *PgPersonRepository) Update(ctx context.Context, person *model.Person) error {
+func (r *PgPersonRepository) Update(ctx context.Context, person *model.Person) (*model.Person, error) {
 	tx, err := r.db.BeginTxx(ctx, nil)
 	if err != nil {
-		return err
+		return nil, translatePgError(err)
 	}
 
 	defer func() {
@@ -252,39 +301,169 @@ func (r *PgPersonRepository) Update(ctx context.Context, person *model.Person) e
 		}
 	}()
 
-	query, args, err := squirrel.
+	// Like a patch, a rename drops the looked up values, they would otherwise
+	// be kept for the new name as the job only fills empty fields.
+	queryString := squirrel.
 		Update("persons").
 		Set("name", person.Name).
 		Set("surname", person.Surname).
 		Set("patronymic", person.Patronymic).
-		Where(squirrel.Eq{"id": person.Id}).
-		PlaceholderFormat(squirrel.Dollar).
-		ToSql()
+		Set("age", clearOnRename(squirrel.Expr("?::integer", person.Age), person.Name)).
+		Set("gender", clearOnRename(squirrel.Expr("?::text", person.Gender), person.Name)).
+		Set("nationality", clearOnRename(squirrel.Expr("?::text", person.Nationality), person.Name)).
+		Set("enrichment_status", pendingOnRename(person.Name))
 
+	updated, err := updatePerson(ctx, tx, model.AuditActionUpdate, queryString, person.Id, person.Version)
 	if err != nil {
-		return fmt.Errorf("failed to build query: %w", err)
+		return nil, err
 	}
 
-	result, err := tx.ExecContext(ctx, query, args...)
+	return updated, nil
+}
+
+func (r *PgPersonRepository) Patch(ctx context.Context, patch *model.PersonPatch) (*model.Person, error) {
+	tx, err := r.db.BeginTxx(ctx, nil)
 	if err != nil {
-		return fmt.Errorf("failed to execute query: %w", err)
+		return nil, translatePgError(err)
 	}
 
-	rowsAffected, err := result.RowsAffected()
+	defer func() {
+		var e error
+		if err == nil {
+			e = tx.Commit()
+		} else {
+			e = tx.Rollback()
+		}
+
+		if err == nil && e != nil {
+			err = fmt.Errorf("finishing transaction: %w", e)
+		}
+	}()
+
+	queryString := squirrel.Update("persons")
+
+	if patch.Name.Set {
+		queryString = queryString.
+			Set("name", patch.Name.Value).
+			Set("enrichment_status", pendingOnRename(*patch.Name.Value))
+
+		// Looked up values the patch does not set belong to the old name.
+		if !patch.Age.Set {
+			queryString = queryString.Set("age", clearOnRename(squirrel.Expr("age"), *patch.Name.Value))
+		}
+		if !patch.Gender.Set {
+			queryString = queryString.Set("gender", clearOnRename(squirrel.Expr("gender"), *patch.Name.Value))
+		}
+		if !patch.Nationality.Set {
+			queryString = queryString.Set("nationality", clearOnRename(squirrel.Expr("nationality"), *patch.Name.Value))
+		}
+	}
+	if patch.Surname.Set {
+		queryString = queryString.Set("surname", patch.Surname.Value)
+	}
+	if patch.Patronymic.Set {
+		queryString = queryString.Set("patronymic", patch.Patronymic.Value)
+	}
+	if patch.Age.Set {
+		queryString = queryString.Set("age", patch.Age.Value)
+	}
+	if patch.Gender.Set {
+		queryString = queryString.Set("gender", patch.Gender.Value)
+	}
+	if patch.Nationality.Set {
+		queryString = queryString.Set("nationality", patch.Nationality.Value)
+	}
+
+	updated, err := updatePerson(ctx, tx, model.AuditActionPatch, queryString, patch.Id, patch.Version)
+	if err != nil {
+		return nil, err
+	}
+
+	return updated, nil
+}
+
+func (r *PgPersonRepository) DeleteById(ctx context.Context, id int, version int) error {
+	tx, err := r.db.BeginTxx(ctx, nil)
 	if err != nil {
-		return fmt.Errorf("failed to get rows affected: %w", err)
+		return translatePgError(err)
 	}
 
-	if rowsAffected == 0 {
-		return fmt.Errorf("person with id %d not found", person.Id)
+	defer func() {
+		var e error
+		if err == nil {
+			e = tx.Commit()
+		} else {
+			e = tx.Rollback()
+		}
+
+		if err == nil && e != nil {
+			err = fmt.Errorf("finishing transaction: %w", e)
+		}
+	}()
+
+	queryString := squirrel.
+		Update("persons").
+		Set("deleted_at", squirrel.Expr("now()"))
+
+	_, err = updatePerson(ctx, tx, model.AuditActionDelete, queryString, id, version)
+	if err != nil {
+		return err
 	}
+
 	return nil
 }
 
-func (r *PgPersonRepository) DeleteById(ctx context.Context, id int) error {
+func (r *PgPersonRepository) Restore(ctx context.Context, id int) (*model.Person, error) {
 	tx, err := r.db.BeginTxx(ctx, nil)
 	if err != nil {
-		return err
+		return nil, translatePgError(err)
+	}
+
+	defer func() {
+		var e error
+		if err == nil {
+			e = tx.Commit()
+		} else {
+			e = tx.Rollback()
+		}
+
+		if err == nil && e != nil {
+			err = fmt.Errorf("finishing transaction: %w", e)
+		}
+	}()
+
+	before, err := lockPerson(ctx, tx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	if before.DeletedAt == nil {
+		err = fmt.Errorf("person with id %d is not deleted: %w", id, model.ErrConflict)
+		return nil, err
+	}
+
+	after, err := execUpdatePerson(ctx, tx, squirrel.Update("persons").Set("deleted_at", nil), id)
+	if err != nil {
+		return nil, err
+	}
+
+	err = insertAudit(ctx, tx, model.AuditActionRestore, id, before, after)
+	if err != nil {
+		return nil, err
+	}
+
+	err = attachCandidates(ctx, tx, after)
+	if err != nil {
+		return nil, err
+	}
+
+	return after, nil
+}
+
+func (r *PgPersonRepository) PurgeDeleted(ctx context.Context, deletedBefore time.Time) (int64, error) {
+	tx, err := r.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return 0, translatePgError(err)
 	}
 
 	defer func() {
@@ -302,23 +481,366 @@ func (r *PgPersonRepository) DeleteById(ctx context.Context, id int) error {
 
 	query, args, err := squirrel.
 		Delete("persons").
+		Where(squirrel.Lt{"deleted_at": deletedBefore}).
+		Suffix("RETURNING " + strings.Join(personColumns, ", ")).
+		PlaceholderFormat(squirrel.Dollar).
+		ToSql()
+
+	if err != nil {
+		return 0, fmt.Errorf("failed to build query: %w", err)
+	}
+
+	var purged []model.Person
+
+	err = tx.SelectContext(ctx, &purged, query, args...)
+	if err != nil {
+		return 0, fmt.Errorf("failed to execute query: %w", translatePgError(err))
+	}
+
+	for i := range purged {
+		err = insertAudit(ctx, tx, model.AuditActionPurge, purged[i].Id, &purged[i], nil)
+		if err != nil {
+			return 0, err
+		}
+	}
+
+	return int64(len(purged)), nil
+}
+
+// updatePerson locks the live row, checks the version the client expects,
+// applies the update and records it in the audit trail. A renamed person is
+// queued for enrichment again.
+func updatePerson(ctx context.Context, tx *sqlx.Tx, action string, queryString squirrel.UpdateBuilder, id int, version int) (*model.Person, error) {
+	before, err := lockPerson(ctx, tx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	if before.DeletedAt != nil {
+		return nil, fmt.Errorf("person with id %d: %w", id, model.ErrNotFound)
+	}
+	if version != model.AnyVersion && before.Version != version {
+		return nil, fmt.Errorf("person with id %d is at version %d: %w", id, before.Version, model.ErrPreconditionFailed)
+	}
+
+	after, err := execUpdatePerson(ctx, tx, queryString, id)
+	if err != nil {
+		return nil, err
+	}
+
+	if !strings.EqualFold(before.Name, after.Name) {
+		err = replaceCandidates(ctx, tx, id, nil, nil)
+		if err != nil {
+			return nil, err
+		}
+
+		err = enqueueEnrichment(ctx, tx, false, id)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	err = insertAudit(ctx, tx, action, id, before, after)
+	if err != nil {
+		return nil, err
+	}
+
+	err = attachCandidates(ctx, tx, after)
+	if err != nil {
+		return nil, err
+	}
+
+	return after, nil
+}
+
+func execUpdatePerson(ctx context.Context, tx *sqlx.Tx, queryString squirrel.UpdateBuilder, id int) (*model.Person, error) {
+	query, args, err := queryString.
+		Set("version", squirrel.Expr("version + 1")).
 		Where(squirrel.Eq{"id": id}).
+		Suffix("RETURNING " + strings.Join(personColumns, ", ")).
 		PlaceholderFormat(squirrel.Dollar).
 		ToSql()
 
-	result, err := tx.ExecContext(ctx, query, args...)
 	if err != nil {
-		return fmt.Errorf("failed to execute query: %w", err)
+		return nil, fmt.Errorf("failed to build query: %w", err)
 	}
 
-	rowsAffected, err := result.RowsAffected()
+	var person model.Person
+
+	err = tx.QueryRowxContext(ctx, query, args...).StructScan(&person)
 	if err != nil {
-		return fmt.Errorf("failed to get rows affected: %w", err)
+		return nil, fmt.Errorf("failed to execute query: %w", translatePgError(err))
 	}
 
-	if rowsAffected == 0 {
-		return fmt.Errorf("person with id %d not found", id)
+	return &person, nil
+}
+
+func lockPerson(ctx context.Context, tx *sqlx.Tx, id int) (*model.Person, error) {
+	query, args, err := squirrel.
+		Select(personColumns...).
+		From("persons").
+		Where(squirrel.Eq{"id": id}).
+		Suffix("FOR UPDATE").
+		PlaceholderFormat(squirrel.Dollar).
+		ToSql()
+
+	if err != nil {
+		return nil, fmt.Errorf("failed to build query: %w", err)
 	}
 
-	return nil
+	var person model.Person
+
+	err = tx.QueryRowxContext(ctx, query, args...).StructScan(&person)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, fmt.Errorf("person with id %d: %w", id, model.ErrNotFound)
+		}
+		return nil, fmt.Errorf("failed to execute query: %w", translatePgError(err))
+	}
+
+	return &person, nil
+}
+
+func selectPersonsPage(ctx context.Context, tx *sqlx.Tx, filter *model.PersonFilter, pagination *model.Pagination) (*model.PersonPage, error) {
+	countQuery, args, err := applyPersonFilter(squirrel.Select("COUNT(*)").From("persons"), filter).
+		PlaceholderFormat(squirrel.Dollar).
+		ToSql()
+
+	if err != nil {
+		return nil, fmt.Errorf("failed to build count query: %w", err)
+	}
+
+	var total int
+
+	err = tx.GetContext(ctx, &total, countQuery, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to execute count query: %w", translatePgError(err))
+	}
+
+	queryString := squirrel.
+		Select(personColumns...).
+		From("persons")
+
+	var sort []model.SortField
+	if filter != nil {
+		sort = filter.Sort
+	}
+	sort, err = effectiveSort(sort)
+	if err != nil {
+		return nil, err
+	}
+
+	queryString = applyPersonFilter(queryString, filter)
+	if pagination.Cursor != nil {
+		seek, err := cursorSeek(sort, pagination.Cursor)
+		if err != nil {
+			return nil, err
+		}
+		queryString = queryString.Where(seek)
+	}
+
+	// one extra row tells whether there is a next page to point the cursor at
+	query, args, err := queryString.
+		OrderBy(orderByClauses(sort)...).
+		Limit(uint64(pagination.Limit() + 1)).
+		Offset(uint64(pagination.Offset())).
+		PlaceholderFormat(squirrel.Dollar).
+		ToSql()
+
+	if err != nil {
+		return nil, fmt.Errorf("failed to build query: %w", err)
+	}
+
+	persons := make([]model.Person, 0, pagination.Limit()+1)
+
+	err = tx.SelectContext(ctx, &persons, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to execute query: %w", translatePgError(err))
+	}
+
+	page := &model.PersonPage{
+		Persons: persons,
+		Total:   total,
+	}
+
+	if len(persons) > pagination.Limit() {
+		page.Persons = persons[:pagination.Limit()]
+		page.NextCursor = newCursor(page.Persons[len(page.Persons)-1], sort)
+	}
+
+	pagePersons := make([]*model.Person, len(page.Persons))
+	for i := range page.Persons {
+		pagePersons[i] = &page.Persons[i]
+	}
+
+	err = attachCandidates(ctx, tx, pagePersons...)
+	if err != nil {
+		return nil, err
+	}
+
+	return page, nil
+}
+
+func applyPersonFilter(queryString squirrel.SelectBuilder, filter *model.PersonFilter) squirrel.SelectBuilder {
+	if filter == nil || !filter.IncludeDeleted {
+		queryString = queryString.Where(squirrel.Eq{"deleted_at": nil})
+	}
+	if filter == nil {
+		return queryString
+	}
+
+	if filter.Name != nil {
+		queryString = queryString.Where(squirrel.Eq{"name": *filter.Name})
+	}
+	if filter.Surname != nil {
+		queryString = queryString.Where(squirrel.Eq{"surname": *filter.Surname})
+	}
+	if filter.Patronymic != nil {
+		queryString = queryString.Where(squirrel.Eq{"patronymic": *filter.Patronymic})
+	}
+	if len(filter.Nationalities) != 0 {
+		queryString = queryString.Where(squirrel.Eq{"nationality": filter.Nationalities})
+	}
+	if len(filter.Genders) != 0 {
+		queryString = queryString.Where(squirrel.Eq{"gender": filter.Genders})
+	}
+
+	if filter.NameLike != nil {
+		queryString = queryString.Where(squirrel.Like{"name": "%" + *filter.NameLike + "%"})
+	}
+	if filter.SurnameLike != nil {
+		queryString = queryString.Where(squirrel.Like{"surname": "%" + *filter.SurnameLike + "%"})
+	}
+	if filter.PatronymicLike != nil {
+		queryString = queryString.Where(squirrel.Like{"patronymic": "%" + *filter.PatronymicLike + "%"})
+	}
+
+	if filter.AgeMax != nil {
+		queryString = queryString.Where(squirrel.LtOrEq{"age": *filter.AgeMax})
+	}
+	if filter.AgeMin != nil {
+		queryString = queryString.Where(squirrel.GtOrEq{"age": *filter.AgeMin})
+	}
+
+	return queryString
+}
+
+var nullablePersonColumns = map[string]bool{
+	"patronymic":  true,
+	"age":         true,
+	"gender":      true,
+	"nationality": true,
+}
+
+func effectiveSort(sort []model.SortField) ([]model.SortField, error) {
+	for _, field := range sort {
+		if !slices.Contains(model.PersonSortColumns, field.Column) {
+			return nil, fmt.Errorf("%w: unexpected sort column %q", model.ErrValidation, field.Column)
+		}
+	}
+
+	return model.EffectiveSort(sort), nil
+}
+
+func orderByClauses(sort []model.SortField) []string {
+	clauses := make([]string, len(sort))
+	for i, field := range sort {
+		if field.Desc {
+			clauses[i] = field.Column + " DESC"
+		} else {
+			clauses[i] = field.Column + " ASC"
+		}
+	}
+
+	return clauses
+}
+
+// newCursor points after last in the effective sort, which always ends with
+// id. The id is kept apart, the other columns make the keys.
+func newCursor(last model.Person, sort []model.SortField) *model.Cursor {
+	keyFields := sort[:len(sort)-1]
+	keys := make([]any, len(keyFields))
+	for i, field := range keyFields {
+		keys[i] = personColumnValue(last, field.Column)
+	}
+
+	return &model.Cursor{
+		Sort: model.FormatSort(sort),
+		Keys: keys,
+		Id:   last.Id,
+	}
+}
+
+func personColumnValue(person model.Person, column string) any {
+	switch column {
+	case "name":
+		return person.Name
+	case "surname":
+		return person.Surname
+	case "patronymic":
+		return derefOrNil(person.Patronymic)
+	case "age":
+		return derefOrNil(person.Age)
+	case "gender":
+		return derefOrNil(person.Gender)
+	case "nationality":
+		return derefOrNil(person.Nationality)
+	default:
+		return person.Id
+	}
+}
+
+func derefOrNil[T any](value *T) any {
+	if value == nil {
+		return nil
+	}
+	return *value
+}
+
+// cursorSeek expands the keyset condition "(k1, ..., kn, id) is after the cursor"
+// into OR-ed prefixes, because the sort directions may differ per column.
+// NULLs are treated as the largest value, matching Postgres' default
+// NULLS LAST for ASC and NULLS FIRST for DESC.
+func cursorSeek(sort []model.SortField, cursor *model.Cursor) (squirrel.Sqlizer, error) {
+	if len(cursor.Keys) != len(sort)-1 {
+		return nil, fmt.Errorf("%w: cursor does not match sort order", model.ErrValidation)
+	}
+	values := append(slices.Clone(cursor.Keys), cursor.Id)
+
+	seek := squirrel.Or{}
+	for i, field := range sort {
+		after := seekAfter(field, values[i])
+		if after == nil {
+			continue
+		}
+
+		prefix := squirrel.And{}
+		for j := 0; j < i; j++ {
+			prefix = append(prefix, squirrel.Eq{sort[j].Column: values[j]})
+		}
+		seek = append(seek, append(prefix, after))
+	}
+
+	if len(seek) == 0 {
+		return squirrel.Expr("FALSE"), nil
+	}
+
+	return seek, nil
+}
+
+func seekAfter(field model.SortField, value any) squirrel.Sqlizer {
+	nullable := nullablePersonColumns[field.Column]
+
+	switch {
+	case field.Desc && value == nil:
+		return squirrel.NotEq{field.Column: nil}
+	case field.Desc:
+		return squirrel.Lt{field.Column: value}
+	case value == nil:
+		return nil
+	case nullable:
+		return squirrel.Or{squirrel.Gt{field.Column: value}, squirrel.Eq{field.Column: nil}}
+	default:
+		return squirrel.Gt{field.Column: value}
+	}
 }
@@ -0,0 +1,165 @@
+package pg
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+
+	"github.com/ivanjabrony/personApi/internal/model"
+)
+
+func TestCursorSeek(t *testing.T) {
+	tests := []struct {
+		name     string
+		sort     []model.SortField
+		cursor   model.Cursor
+		wantSql  string
+		wantArgs []any
+	}{
+		{
+			name:     "id ascending",
+			sort:     []model.SortField{{Column: "id"}},
+			cursor:   model.Cursor{Id: 5},
+			wantSql:  "((id > ?))",
+			wantArgs: []any{5},
+		},
+		{
+			name:     "id descending",
+			sort:     []model.SortField{{Column: "id", Desc: true}},
+			cursor:   model.Cursor{Id: 5},
+			wantSql:  "((id < ?))",
+			wantArgs: []any{5},
+		},
+		{
+			name:     "not null column breaks ties by id",
+			sort:     []model.SortField{{Column: "name"}, {Column: "id"}},
+			cursor:   model.Cursor{Keys: []any{"Ann"}, Id: 5},
+			wantSql:  "((name > ?) OR (name = ? AND id > ?))",
+			wantArgs: []any{"Ann", "Ann", 5},
+		},
+		{
+			name:     "ascending nullable column is followed by nulls",
+			sort:     []model.SortField{{Column: "age"}, {Column: "id"}},
+			cursor:   model.Cursor{Keys: []any{30.0}, Id: 5},
+			wantSql:  "(((age > ? OR age IS NULL)) OR (age = ? AND id > ?))",
+			wantArgs: []any{30.0, 30.0, 5},
+		},
+		{
+			name:     "ascending null key only has nulls after it",
+			sort:     []model.SortField{{Column: "age"}, {Column: "id"}},
+			cursor:   model.Cursor{Keys: []any{nil}, Id: 5},
+			wantSql:  "((age IS NULL AND id > ?))",
+			wantArgs: []any{5},
+		},
+		{
+			name:     "descending null key is followed by every value",
+			sort:     []model.SortField{{Column: "age", Desc: true}, {Column: "id"}},
+			cursor:   model.Cursor{Keys: []any{nil}, Id: 5},
+			wantSql:  "((age IS NOT NULL) OR (age IS NULL AND id > ?))",
+			wantArgs: []any{5},
+		},
+		{
+			name:     "mixed directions",
+			sort:     []model.SortField{{Column: "age", Desc: true}, {Column: "surname"}, {Column: "id", Desc: true}},
+			cursor:   model.Cursor{Keys: []any{30.0, "Smith"}, Id: 5},
+			wantSql:  "((age < ?) OR (age = ? AND surname > ?) OR (age = ? AND surname = ? AND id < ?))",
+			wantArgs: []any{30.0, 30.0, "Smith", 30.0, "Smith", 5},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			seek, err := cursorSeek(tt.sort, &tt.cursor)
+			if err != nil {
+				t.Fatalf("cursorSeek() error = %v", err)
+			}
+
+			sql, args, err := seek.ToSql()
+			if err != nil {
+				t.Fatalf("ToSql() error = %v", err)
+			}
+			if sql != tt.wantSql {
+				t.Errorf("sql = %q, want %q", sql, tt.wantSql)
+			}
+			if !reflect.DeepEqual(args, tt.wantArgs) {
+				t.Errorf("args = %v, want %v", args, tt.wantArgs)
+			}
+		})
+	}
+}
+
+func TestCursorSeekRejectsForeignCursor(t *testing.T) {
+	sort := []model.SortField{{Column: "name"}, {Column: "id"}}
+
+	_, err := cursorSeek(sort, &model.Cursor{Id: 5})
+	if !errors.Is(err, model.ErrValidation) {
+		t.Fatalf("cursorSeek() error = %v, want %v", err, model.ErrValidation)
+	}
+}
+
+func TestSeekAfter(t *testing.T) {
+	tests := []struct {
+		name    string
+		field   model.SortField
+		value   any
+		wantSql string
+		wantNil bool
+	}{
+		{name: "ascending", field: model.SortField{Column: "name"}, value: "Ann", wantSql: "name > ?"},
+		{name: "descending", field: model.SortField{Column: "name", Desc: true}, value: "Ann", wantSql: "name < ?"},
+		{name: "ascending nullable", field: model.SortField{Column: "gender"}, value: "male", wantSql: "(gender > ? OR gender IS NULL)"},
+		{name: "descending nullable", field: model.SortField{Column: "gender", Desc: true}, value: "male", wantSql: "gender < ?"},
+		{name: "ascending null", field: model.SortField{Column: "gender"}, value: nil, wantNil: true},
+		{name: "descending null", field: model.SortField{Column: "gender", Desc: true}, value: nil, wantSql: "gender IS NOT NULL"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			after := seekAfter(tt.field, tt.value)
+			if tt.wantNil {
+				if after != nil {
+					t.Fatalf("seekAfter() = %v, want nil", after)
+				}
+				return
+			}
+
+			sql, _, err := after.ToSql()
+			if err != nil {
+				t.Fatalf("ToSql() error = %v", err)
+			}
+			if sql != tt.wantSql {
+				t.Errorf("sql = %q, want %q", sql, tt.wantSql)
+			}
+		})
+	}
+}
+
+func TestCursorRoundTrip(t *testing.T) {
+	age := 30
+	last := model.Person{Id: 5, Name: "Ann", Surname: "Smith", Age: &age}
+
+	for _, raw := range []string{"id", "-id", "name,id", "name,id,age", "-age", ""} {
+		t.Run(raw, func(t *testing.T) {
+			parsed, err := model.ParseSort(raw)
+			if err != nil {
+				t.Fatalf("ParseSort() error = %v", err)
+			}
+
+			sort, err := effectiveSort(parsed)
+			if err != nil {
+				t.Fatalf("effectiveSort() error = %v", err)
+			}
+
+			decoded, err := model.DecodeCursor(newCursor(last, sort).Encode())
+			if err != nil {
+				t.Fatalf("DecodeCursor() error = %v", err)
+			}
+			if want := model.FormatSort(model.EffectiveSort(parsed)); decoded.Sort != want {
+				t.Errorf("cursor sort = %q, want %q", decoded.Sort, want)
+			}
+			if _, err := cursorSeek(sort, decoded); err != nil {
+				t.Errorf("cursorSeek() error = %v", err)
+			}
+		})
+	}
+}
@@ -0,0 +1,36 @@
+package repository_metrics
+
+import (
+	"context"
+	"time"
+
+	"github.com/ivanjabrony/personApi/internal/repository"
+)
+
+const enrichmentCacheRepositoryName = "enrichment_cache"
+
+type InstrumentedEnrichmentCacheRepository struct {
+	next     repository.EnrichmentCacheRepository
+	observer QueryObserver
+}
+
+func NewInstrumentedEnrichmentCacheRepository(next repository.EnrichmentCacheRepository, observer QueryObserver) *InstrumentedEnrichmentCacheRepository {
+	return &InstrumentedEnrichmentCacheRepository{next, observer}
+}
+
+func (r *InstrumentedEnrichmentCacheRepository) Get(ctx context.Context, kind string, name string) ([]byte, bool, error) {
+	var found bool
+	value, err := observe(r.observer, enrichmentCacheRepositoryName, "Get", func() ([]byte, error) {
+		value, ok, err := r.next.Get(ctx, kind, name)
+		found = ok
+		return value, err
+	})
+
+	return value, found, err
+}
+
+func (r *InstrumentedEnrichmentCacheRepository) Set(ctx context.Context, kind string, name string, value []byte, expiresAt time.Time) error {
+	return observeErr(r.observer, enrichmentCacheRepositoryName, "Set", func() error {
+		return r.next.Set(ctx, kind, name, value, expiresAt)
+	})
+}
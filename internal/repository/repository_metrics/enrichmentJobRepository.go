@@ -0,0 +1,62 @@
+package repository_metrics
+
+import (
+	"context"
+	"time"
+
+	"github.com/ivanjabrony/personApi/internal/model"
+	"github.com/ivanjabrony/personApi/internal/repository"
+)
+
+const enrichmentJobRepositoryName = "enrichment_job"
+
+type InstrumentedEnrichmentJobRepository struct {
+	next     repository.EnrichmentJobRepository
+	observer QueryObserver
+}
+
+func NewInstrumentedEnrichmentJobRepository(next repository.EnrichmentJobRepository, observer QueryObserver) *InstrumentedEnrichmentJobRepository {
+	return &InstrumentedEnrichmentJobRepository{next, observer}
+}
+
+func (r *InstrumentedEnrichmentJobRepository) Claim(ctx context.Context, limit int, lease time.Duration) ([]model.EnrichmentJob, error) {
+	return observe(r.observer, enrichmentJobRepositoryName, "Claim", func() ([]model.EnrichmentJob, error) {
+		return r.next.Claim(ctx, limit, lease)
+	})
+}
+
+func (r *InstrumentedEnrichmentJobRepository) Complete(ctx context.Context, job *model.EnrichmentJob, enrichment *model.Enrichment) error {
+	return observeErr(r.observer, enrichmentJobRepositoryName, "Complete", func() error {
+		return r.next.Complete(ctx, job, enrichment)
+	})
+}
+
+func (r *InstrumentedEnrichmentJobRepository) Retry(ctx context.Context, job *model.EnrichmentJob, progress *model.EnrichmentProgress, runAt time.Time, lastError string) error {
+	return observeErr(r.observer, enrichmentJobRepositoryName, "Retry", func() error {
+		return r.next.Retry(ctx, job, progress, runAt, lastError)
+	})
+}
+
+func (r *InstrumentedEnrichmentJobRepository) DeadLetter(ctx context.Context, job *model.EnrichmentJob, progress *model.EnrichmentProgress, lastError string) error {
+	return observeErr(r.observer, enrichmentJobRepositoryName, "DeadLetter", func() error {
+		return r.next.DeadLetter(ctx, job, progress, lastError)
+	})
+}
+
+func (r *InstrumentedEnrichmentJobRepository) Enqueue(ctx context.Context, personId int, overwrite bool) error {
+	return observeErr(r.observer, enrichmentJobRepositoryName, "Enqueue", func() error {
+		return r.next.Enqueue(ctx, personId, overwrite)
+	})
+}
+
+func (r *InstrumentedEnrichmentJobRepository) EnqueueStale(ctx context.Context, afterId int, limit int, staleBefore time.Time) ([]int, error) {
+	return observe(r.observer, enrichmentJobRepositoryName, "EnqueueStale", func() ([]int, error) {
+		return r.next.EnqueueStale(ctx, afterId, limit, staleBefore)
+	})
+}
+
+func (r *InstrumentedEnrichmentJobRepository) GetByPersonId(ctx context.Context, personId int) (*model.EnrichmentJob, error) {
+	return observe(r.observer, enrichmentJobRepositoryName, "GetByPersonId", func() (*model.EnrichmentJob, error) {
+		return r.next.GetByPersonId(ctx, personId)
+	})
+}
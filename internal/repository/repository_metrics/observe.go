@@ -0,0 +1,26 @@
+package repository_metrics
+
+import (
+	"time"
+)
+
+// QueryObserver is told how long every repository call took and whether it failed.
+type QueryObserver interface {
+	ObserveQuery(repository string, method string, duration time.Duration, err error)
+}
+
+func observe[T any](observer QueryObserver, repository string, method string, call func() (T, error)) (T, error) {
+	start := time.Now()
+	value, err := call()
+	observer.ObserveQuery(repository, method, time.Since(start), err)
+
+	return value, err
+}
+
+func observeErr(observer QueryObserver, repository string, method string, call func() error) error {
+	start := time.Now()
+	err := call()
+	observer.ObserveQuery(repository, method, time.Since(start), err)
+
+	return err
+}
@@ -0,0 +1,86 @@
+package repository_metrics
+
+import (
+	"context"
+	"time"
+
+	"github.com/ivanjabrony/personApi/internal/model"
+	"github.com/ivanjabrony/personApi/internal/repository"
+)
+
+const personRepositoryName = "person"
+
+type InstrumentedPersonRepository struct {
+	next     repository.PersonRepository
+	observer QueryObserver
+}
+
+func NewInstrumentedPersonRepository(next repository.PersonRepository, observer QueryObserver) *InstrumentedPersonRepository {
+	return &InstrumentedPersonRepository{next, observer}
+}
+
+func (r *InstrumentedPersonRepository) Create(ctx context.Context, person *model.Person) (int, error) {
+	return observe(r.observer, personRepositoryName, "Create", func() (int, error) {
+		return r.next.Create(ctx, person)
+	})
+}
+
+func (r *InstrumentedPersonRepository) CreateMany(ctx context.Context, persons []model.Person) ([]int, error) {
+	return observe(r.observer, personRepositoryName, "CreateMany", func() ([]int, error) {
+		return r.next.CreateMany(ctx, persons)
+	})
+}
+
+func (r *InstrumentedPersonRepository) GetById(ctx context.Context, id int, includeDeleted bool) (*model.Person, error) {
+	return observe(r.observer, personRepositoryName, "GetById", func() (*model.Person, error) {
+		return r.next.GetById(ctx, id, includeDeleted)
+	})
+}
+
+func (r *InstrumentedPersonRepository) GetAll(ctx context.Context, pagination *model.Pagination) (*model.PersonPage, error) {
+	return observe(r.observer, personRepositoryName, "GetAll", func() (*model.PersonPage, error) {
+		return r.next.GetAll(ctx, pagination)
+	})
+}
+
+func (r *InstrumentedPersonRepository) GetFiltered(ctx context.Context, filter *model.PersonFilter, pagination *model.Pagination) (*model.PersonPage, error) {
+	return observe(r.observer, personRepositoryName, "GetFiltered", func() (*model.PersonPage, error) {
+		return r.next.GetFiltered(ctx, filter, pagination)
+	})
+}
+
+func (r *InstrumentedPersonRepository) Update(ctx context.Context, person *model.Person) (*model.Person, error) {
+	return observe(r.observer, personRepositoryName, "Update", func() (*model.Person, error) {
+		return r.next.Update(ctx, person)
+	})
+}
+
+func (r *InstrumentedPersonRepository) Patch(ctx context.Context, patch *model.PersonPatch) (*model.Person, error) {
+	return observe(r.observer, personRepositoryName, "Patch", func() (*model.Person, error) {
+		return r.next.Patch(ctx, patch)
+	})
+}
+
+func (r *InstrumentedPersonRepository) DeleteById(ctx context.Context, id int, version int) error {
+	return observeErr(r.observer, personRepositoryName, "DeleteById", func() error {
+		return r.next.DeleteById(ctx, id, version)
+	})
+}
+
+func (r *InstrumentedPersonRepository) Restore(ctx context.Context, id int) (*model.Person, error) {
+	return observe(r.observer, personRepositoryName, "Restore", func() (*model.Person, error) {
+		return r.next.Restore(ctx, id)
+	})
+}
+
+func (r *InstrumentedPersonRepository) PurgeDeleted(ctx context.Context, deletedBefore time.Time) (int64, error) {
+	return observe(r.observer, personRepositoryName, "PurgeDeleted", func() (int64, error) {
+		return r.next.PurgeDeleted(ctx, deletedBefore)
+	})
+}
+
+func (r *InstrumentedPersonRepository) GetHistory(ctx context.Context, id int, pagination *model.Pagination) (*model.PersonAuditPage, error) {
+	return observe(r.observer, personRepositoryName, "GetHistory", func() (*model.PersonAuditPage, error) {
+		return r.next.GetHistory(ctx, id, pagination)
+	})
+}
@@ -0,0 +1,11 @@
+package service
+
+import (
+	"context"
+
+	"github.com/ivanjabrony/personApi/internal/model/dto"
+)
+
+type DiagnosticsService interface {
+	GetUpstreamStatuses(context.Context) []dto.UpstreamStatusDto
+}
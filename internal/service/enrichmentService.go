@@ -0,0 +1,15 @@
+package service
+
+import (
+	"context"
+	"time"
+
+	"github.com/ivanjabrony/personApi/internal/model/dto"
+)
+
+type EnrichmentService interface {
+	ProcessEnrichmentJobs(context.Context, int) (int, error)
+	GetEnrichmentStatus(context.Context, int) (*dto.EnrichmentStatusDto, error)
+	RequestEnrichment(context.Context, int) (*dto.EnrichmentStatusDto, error)
+	EnqueueStaleEnrichment(context.Context, int, int, time.Duration) ([]int, error)
+}
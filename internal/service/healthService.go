@@ -0,0 +1,12 @@
+package service
+
+import (
+	"context"
+
+	"github.com/ivanjabrony/personApi/internal/model/dto"
+)
+
+type HealthService interface {
+	CheckReadiness(context.Context) *dto.HealthDto
+	GetHealthDetails(context.Context) *dto.HealthDto
+}
@@ -2,6 +2,7 @@ package service
 
 import (
 	"context"
+	"time"
 
 	"github.com/ivanjabrony/personApi/internal/model"
 	"github.com/ivanjabrony/personApi/internal/model/dto"
@@ -9,9 +10,14 @@ import (
 
 type PersonService interface {
 	CreatePerson(context.Context, *dto.NewPersonDto) (int, error)
-	GetPersonById(context.Context, int) (*dto.PersonDto, error)
-	GetAllPersons(context.Context) ([]dto.PersonDto, error)
-	GetPersonsFiltered(context.Context, *model.PersonFilter) ([]dto.PersonDto, error)
-	UpdatePersonById(context.Context, *dto.UpdatePersonDto) error
-	DeletePersonById(context.Context, int) error
+	CreatePersons(context.Context, []dto.NewPersonDto) ([]int, error)
+	GetPersonById(context.Context, int, bool) (*dto.PersonDto, error)
+	GetAllPersons(context.Context, *model.Pagination) (*dto.PaginatedPersonsDto, error)
+	GetPersonsFiltered(context.Context, *model.PersonFilter, *model.Pagination) (*dto.PaginatedPersonsDto, error)
+	UpdatePersonById(context.Context, int, int, *dto.UpdatePersonDto) (*dto.PersonDto, error)
+	PatchPersonById(context.Context, int, int, *dto.PatchPersonDto) (*dto.PersonDto, error)
+	DeletePersonById(context.Context, int, int) error
+	RestorePersonById(context.Context, int) (*dto.PersonDto, error)
+	PurgeDeletedPersons(context.Context, time.Duration) (*dto.PurgeResultDto, error)
+	GetPersonHistory(context.Context, int, *model.Pagination) (*dto.PaginatedPersonAuditDto, error)
 }
@@ -0,0 +1,28 @@
+package service_impl
+
+import (
+	"context"
+
+	"github.com/ivanjabrony/personApi/internal/client"
+	"github.com/ivanjabrony/personApi/internal/mapper"
+	"github.com/ivanjabrony/personApi/internal/model/dto"
+)
+
+type DiagnosticsService struct {
+	upstreamMonitor client.UpstreamMonitor
+}
+
+func NewDiagnosticsService(upstreamMonitor client.UpstreamMonitor) *DiagnosticsService {
+	return &DiagnosticsService{upstreamMonitor}
+}
+
+func (service *DiagnosticsService) GetUpstreamStatuses(ctx context.Context) []dto.UpstreamStatusDto {
+	statuses := service.upstreamMonitor.UpstreamStatuses()
+
+	result := make([]dto.UpstreamStatusDto, 0, len(statuses))
+	for _, status := range statuses {
+		result = append(result, mapper.MapToUpstreamStatusDto(status))
+	}
+
+	return result
+}
@@ -0,0 +1,219 @@
+package service_impl
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/ivanjabrony/personApi/internal/model"
+	"github.com/ivanjabrony/personApi/internal/tracing"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// enrichment is what the lookups of a name answered, err joins the errors of
+// the lookups without an answer.
+type enrichment struct {
+	model.EnrichmentProgress
+	err error
+}
+
+// enrichmentRequest is a name to look up with the country the person gave, if
+// any, the traceparent of the request that asked for it and the answers
+// earlier attempts already got.
+type enrichmentRequest struct {
+	name        string
+	countryHint string
+	traceParent *string
+	known       model.EnrichmentProgress
+}
+
+func (r enrichmentRequest) key() string {
+	return enrichmentKey(r.name) + "@" + r.countryHint + "#" + strings.Join(r.known.Answered(), ",")
+}
+
+// enrich looks a name up, skipping the lookups known already has answers for.
+// Without a country hint the age and gender are asked again for the most
+// likely nationality, a localized answer replaces the generic one when the
+// upstream has it. A failed localized lookup keeps the generic answer.
+func (service *EnrichmentService) enrich(ctx context.Context, request enrichmentRequest) enrichment {
+	result := service.lookup(ctx, request.name, request.countryHint, request.known)
+	if request.countryHint != "" || result.err != nil {
+		return result
+	}
+
+	top := result.Nationality.Value.Top()
+	if top == nil {
+		return result
+	}
+
+	// Answers of earlier attempts are kept as they are.
+	localized := service.lookup(ctx, request.name, top.CountryId, model.EnrichmentProgress{
+		Age:         request.known.Age,
+		Gender:      request.known.Gender,
+		Nationality: result.Nationality,
+	})
+	if localized.err != nil {
+		service.logger.Warn("Couldn't localize enrichment, keeping the generic one",
+			slog.String("CountryId", top.CountryId), slog.String("Error", localized.err.Error()))
+	}
+
+	if localized.Age.Value != nil {
+		result.Age = localized.Age
+	}
+	if localized.Gender.Value != nil {
+		result.Gender = localized.Gender
+	}
+
+	return result
+}
+
+// lookup runs the lookups known has no answer for concurrently, each under
+// its own deadline, so a slow upstream only costs its own field. Failed
+// lookups are joined into err, a name the upstream does not know is not an
+// error.
+func (service *EnrichmentService) lookup(ctx context.Context, name string, countryId string, known model.EnrichmentProgress) enrichment {
+	var (
+		result                            = enrichment{EnrichmentProgress: known}
+		ageErr, genderErr, nationalityErr error
+		wg                                sync.WaitGroup
+	)
+
+	if !known.Age.Set {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			lookupCtx, cancel := service.lookupContext(ctx)
+			defer cancel()
+
+			age, err := service.ageclient.GetAgeByName(lookupCtx, name, countryId)
+			if err != nil {
+				service.logger.Warn("Couldn't retrieve data from Age client", slog.String("Error", err.Error()))
+				ageErr = fmt.Errorf("age lookup: %w", err)
+				return
+			}
+			result.Age = model.Optional[model.AgeEstimate]{Set: true, Value: age}
+		}()
+	}
+	if !known.Gender.Set {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			lookupCtx, cancel := service.lookupContext(ctx)
+			defer cancel()
+
+			gender, err := service.genderClient.GetGenderByName(lookupCtx, name, countryId)
+			if err != nil {
+				service.logger.Warn("Couldn't retrieve data from Gender client", slog.String("Error", err.Error()))
+				genderErr = fmt.Errorf("gender lookup: %w", err)
+				return
+			}
+			result.Gender = model.Optional[model.GenderEstimate]{Set: true, Value: gender}
+		}()
+	}
+	if !known.Nationality.Set {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			lookupCtx, cancel := service.lookupContext(ctx)
+			defer cancel()
+
+			nationality, err := service.nationalityClient.GetNationalityByName(lookupCtx, name)
+			if err != nil {
+				service.logger.Warn("Couldn't retrieve data from Nationality client", slog.String("Error", err.Error()))
+				nationalityErr = fmt.Errorf("nationality lookup: %w", err)
+				return
+			}
+			result.Nationality = model.Optional[model.NationalityEstimate]{Set: true, Value: nationality}
+		}()
+	}
+	wg.Wait()
+
+	result.err = errors.Join(ageErr, genderErr, nationalityErr)
+	return result
+}
+
+// lookupContext derives the context of a single lookup. It is bounded by
+// LookupTimeout and, when ctx has a deadline, by three quarters of the time
+// remaining until it.
+func (service *EnrichmentService) lookupContext(ctx context.Context) (context.Context, context.CancelFunc) {
+	timeout := service.config.LookupTimeout
+	if deadline, ok := ctx.Deadline(); ok {
+		remaining := time.Until(deadline) * 3 / 4
+		if timeout <= 0 || remaining < timeout {
+			timeout = max(remaining, 0)
+		}
+	}
+
+	if timeout <= 0 && service.config.LookupTimeout <= 0 {
+		return context.WithCancel(ctx)
+	}
+	return context.WithTimeout(ctx, timeout)
+}
+
+// enrichMany looks every distinct request up once, running at most
+// EnrichmentConcurrency of them at a time. Results are keyed by request key.
+func (service *EnrichmentService) enrichMany(ctx context.Context, requests []enrichmentRequest) map[string]enrichment {
+	unique := make(map[string]enrichmentRequest, len(requests))
+	for _, request := range requests {
+		if previous, ok := unique[request.key()]; ok && previous.traceParent != nil {
+			continue
+		}
+		unique[request.key()] = request
+	}
+
+	var (
+		wg      sync.WaitGroup
+		mu      sync.Mutex
+		results = make(map[string]enrichment, len(unique))
+		limiter = make(chan struct{}, max(service.config.EnrichmentConcurrency, 1))
+	)
+
+	for key, request := range unique {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+
+			select {
+			case limiter <- struct{}{}:
+				defer func() { <-limiter }()
+			case <-ctx.Done():
+				return
+			}
+
+			result := service.tracedEnrich(ctx, request)
+
+			mu.Lock()
+			results[key] = result
+			mu.Unlock()
+		}()
+	}
+	wg.Wait()
+
+	return results
+}
+
+// tracedEnrich runs enrich in a span that continues the trace of the request
+// which queued the lookup, so its upstream calls show up under that request.
+// Such a span links back to the batch it was processed in.
+func (service *EnrichmentService) tracedEnrich(ctx context.Context, request enrichmentRequest) enrichment {
+	options := []trace.SpanStartOption{trace.WithAttributes(attribute.String("enrichment.country_hint", request.countryHint))}
+	if request.traceParent != nil {
+		options = append(options, trace.WithLinks(trace.LinkFromContext(ctx)))
+	}
+
+	ctx, span := tracer.Start(tracing.RemoteContext(ctx, request.traceParent), "EnrichmentService.Enrich", options...)
+
+	result := service.enrich(ctx, request)
+	tracing.End(span, result.err)
+
+	return result
+}
+
+func enrichmentKey(name string) string {
+	return strings.ToLower(name)
+}
@@ -0,0 +1,220 @@
+package service_impl
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"math/rand/v2"
+	"time"
+
+	"github.com/ivanjabrony/personApi/internal/client"
+	"github.com/ivanjabrony/personApi/internal/mapper"
+	"github.com/ivanjabrony/personApi/internal/model"
+	"github.com/ivanjabrony/personApi/internal/model/dto"
+	"github.com/ivanjabrony/personApi/internal/repository"
+	"github.com/ivanjabrony/personApi/internal/tracing"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+)
+
+var tracer = otel.Tracer("github.com/ivanjabrony/personApi/internal/service")
+
+type EnrichmentServiceConfig struct {
+	EnrichmentConcurrency int
+	LookupTimeout         time.Duration
+	JobLease              time.Duration
+	MaxAttempts           int
+	RetryBackoff          time.Duration
+	MaxRetryBackoff       time.Duration
+}
+
+type EnrichmentService struct {
+	personRepository  repository.PersonRepository
+	jobRepository     repository.EnrichmentJobRepository
+	logger            *slog.Logger
+	ageclient         client.AgeClient
+	genderClient      client.GenderClient
+	nationalityClient client.NationalityClient
+	config            EnrichmentServiceConfig
+}
+
+func NewEnrichmentService(
+	personRepository repository.PersonRepository,
+	jobRepository repository.EnrichmentJobRepository,
+	ageclient client.AgeClient,
+	genderClient client.GenderClient,
+	nationalityClient client.NationalityClient,
+	logger *slog.Logger,
+	config EnrichmentServiceConfig) *EnrichmentService {
+	return &EnrichmentService{personRepository, jobRepository, logger, ageclient, genderClient, nationalityClient, config}
+}
+
+// ProcessEnrichmentJobs claims up to limit queued jobs, looks their names up
+// and settles every job: completed, rescheduled or dead-lettered once it has
+// used up MaxAttempts. It returns how many jobs were claimed.
+func (service *EnrichmentService) ProcessEnrichmentJobs(ctx context.Context, limit int) (int, error) {
+	jobs, err := service.jobRepository.Claim(ctx, limit, service.config.JobLease)
+	if err != nil {
+		service.logger.Error("Repository error while claiming enrichment jobs", slog.String("Error", err.Error()))
+		return 0, err
+	}
+
+	if len(jobs) == 0 {
+		return 0, nil
+	}
+
+	ctx, span := tracer.Start(ctx, "EnrichmentService.ProcessEnrichmentJobs",
+		trace.WithNewRoot(),
+		trace.WithAttributes(attribute.Int("jobs.count", len(jobs))),
+		trace.WithLinks(jobLinks(jobs)...))
+	defer span.End()
+
+	service.logger.Debug("Start of enrichment jobs processing", slog.Int("Count", len(jobs)))
+
+	requests := make([]enrichmentRequest, len(jobs))
+	for i := range jobs {
+		requests[i], err = jobRequest(&jobs[i])
+		if err != nil {
+			service.logger.Warn("Couldn't read enrichment job progress, repeating every lookup",
+				slog.Int("ID", jobs[i].PersonId), slog.String("Error", err.Error()))
+		}
+	}
+
+	results := service.enrichMany(ctx, requests)
+	if ctx.Err() != nil {
+		// Claimed jobs become runnable again once their lease runs out.
+		return len(jobs), ctx.Err()
+	}
+
+	for i := range jobs {
+		service.settleJob(ctx, &jobs[i], results[requests[i].key()])
+	}
+
+	return len(jobs), nil
+}
+
+// jobRequest asks for the lookups of the job earlier attempts got no answer
+// for, all of them when its progress can't be read.
+func jobRequest(job *model.EnrichmentJob) (enrichmentRequest, error) {
+	request := enrichmentRequest{name: job.Name, traceParent: job.TraceParent}
+	if job.CountryHint != nil {
+		request.countryHint = *job.CountryHint
+	}
+
+	known, err := job.DecodeProgress()
+	if err != nil {
+		return request, err
+	}
+	request.known = known
+
+	return request, nil
+}
+
+// jobLinks links the batch to the traces of the requests that queued its jobs.
+func jobLinks(jobs []model.EnrichmentJob) []trace.Link {
+	var links []trace.Link
+	for i := range jobs {
+		origin := trace.SpanContextFromContext(tracing.RemoteContext(context.Background(), jobs[i].TraceParent))
+		if origin.IsValid() {
+			links = append(links, trace.Link{SpanContext: origin})
+		}
+	}
+
+	return links
+}
+
+func (service *EnrichmentService) settleJob(ctx context.Context, job *model.EnrichmentJob, result enrichment) {
+	var err error
+	switch {
+	case result.err == nil:
+		err = service.jobRepository.Complete(ctx, job, result.Enrichment())
+		if err == nil {
+			service.logger.Info("Person successfully enriched", slog.Int("ID", job.PersonId), slog.Int("Attempt", job.Attempts))
+		}
+	case job.Attempts >= service.config.MaxAttempts:
+		err = service.jobRepository.DeadLetter(ctx, job, &result.EnrichmentProgress, result.err.Error())
+		if err == nil {
+			service.logger.Error("Person enrichment failed permanently", slog.Int("ID", job.PersonId), slog.Int("Attempt", job.Attempts), slog.String("Error", result.err.Error()))
+		}
+	default:
+		delay := service.retryDelay(job.Attempts)
+		err = service.jobRepository.Retry(ctx, job, &result.EnrichmentProgress, time.Now().Add(delay), result.err.Error())
+		if err == nil {
+			service.logger.Warn("Person enrichment rescheduled", slog.Int("ID", job.PersonId), slog.Int("Attempt", job.Attempts), slog.Duration("Delay", delay))
+		}
+	}
+
+	if errors.Is(err, model.ErrConflict) {
+		service.logger.Warn("Enrichment job was superseded before it was settled", slog.Int("ID", job.PersonId), slog.String("Error", err.Error()))
+	} else if err != nil {
+		service.logger.Error("Repository error while settling enrichment job", slog.Int("ID", job.PersonId), slog.String("Error", err.Error()))
+	}
+}
+
+// retryDelay doubles RetryBackoff with every attempt up to MaxRetryBackoff and
+// randomizes the upper half so jobs failed by one outage do not retry in step.
+func (service *EnrichmentService) retryDelay(attempt int) time.Duration {
+	delay := service.config.RetryBackoff
+	for i := 1; i < attempt && delay < service.config.MaxRetryBackoff; i++ {
+		delay *= 2
+	}
+	delay = min(delay, service.config.MaxRetryBackoff)
+
+	if delay <= 0 {
+		return 0
+	}
+	return delay/2 + rand.N(delay/2+1)
+}
+
+func (service *EnrichmentService) GetEnrichmentStatus(ctx context.Context, personId int) (*dto.EnrichmentStatusDto, error) {
+	service.logger.Debug("Start of reading enrichment status", slog.Int("ID", personId))
+	person, err := service.personRepository.GetById(ctx, personId, false)
+
+	if err != nil {
+		service.logger.Error("Repository error while reading", slog.String("Error", err.Error()))
+		return nil, err
+	}
+
+	job, err := service.jobRepository.GetByPersonId(ctx, personId)
+	if errors.Is(err, model.ErrNotFound) {
+		job, err = nil, nil
+	}
+
+	if err != nil {
+		service.logger.Error("Repository error while reading enrichment job", slog.String("Error", err.Error()))
+		return nil, err
+	}
+
+	service.logger.Info("Enrichment status successfully retrieved", slog.Int("ID", personId))
+	return mapper.MapToEnrichmentStatusDto(person, job, service.config.MaxAttempts), nil
+}
+
+// RequestEnrichment queues a fresh lookup for the person that replaces the
+// current age, gender and nationality once it succeeds.
+func (service *EnrichmentService) RequestEnrichment(ctx context.Context, personId int) (*dto.EnrichmentStatusDto, error) {
+	service.logger.Debug("Start of enrichment requesting", slog.Int("ID", personId))
+	err := service.jobRepository.Enqueue(ctx, personId, true)
+
+	if err != nil {
+		service.logger.Error("Repository error while requesting enrichment", slog.String("Error", err.Error()))
+		return nil, err
+	}
+
+	service.logger.Info("Enrichment successfully requested", slog.Int("ID", personId))
+	return service.GetEnrichmentStatus(ctx, personId)
+}
+
+func (service *EnrichmentService) EnqueueStaleEnrichment(ctx context.Context, afterId int, limit int, staleAfter time.Duration) ([]int, error) {
+	staleBefore := time.Now().Add(-staleAfter)
+	service.logger.Debug("Start of stale enrichment queueing", slog.Int("AfterID", afterId), slog.Time("StaleBefore", staleBefore))
+	ids, err := service.jobRepository.EnqueueStale(ctx, afterId, limit, staleBefore)
+
+	if err != nil {
+		service.logger.Error("Repository error while queueing stale enrichment", slog.String("Error", err.Error()))
+		return nil, err
+	}
+
+	service.logger.Info("Stale enrichment successfully queued", slog.Int("Count", len(ids)))
+	return ids, nil
+}
@@ -0,0 +1,72 @@
+package service_impl
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/ivanjabrony/personApi/internal/model/dto"
+)
+
+type checkResult struct {
+	err       error
+	latency   time.Duration
+	checkedAt time.Time
+}
+
+// cachedCheck runs a dependency check at most once per ttl, concurrent
+// probes wait for the running check instead of starting their own.
+type cachedCheck struct {
+	name    string
+	ttl     time.Duration
+	timeout time.Duration
+	run     func(context.Context) error
+
+	mu   sync.Mutex
+	last *checkResult
+}
+
+func newCachedCheck(name string, config HealthServiceConfig, run func(context.Context) error) *cachedCheck {
+	return &cachedCheck{name: name, ttl: config.CacheTTL, timeout: config.CheckTimeout, run: run}
+}
+
+func (c *cachedCheck) get(ctx context.Context) checkResult {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.last != nil && time.Since(c.last.checkedAt) < c.ttl {
+		return *c.last
+	}
+
+	checkCtx, cancel := context.WithTimeout(ctx, c.timeout)
+	defer cancel()
+
+	start := time.Now()
+	err := c.run(checkCtx)
+	result := checkResult{err: err, latency: time.Since(start), checkedAt: time.Now()}
+
+	// a probe that gave up says nothing about the dependency
+	if ctx.Err() == nil {
+		c.last = &result
+	}
+
+	return result
+}
+
+func (c *cachedCheck) check(ctx context.Context) dto.HealthCheckDto {
+	result := c.get(ctx)
+
+	check := dto.HealthCheckDto{
+		Name:      c.name,
+		Status:    dto.HealthStatusUp,
+		LatencyMs: float64(result.latency.Microseconds()) / 1000,
+		CheckedAt: result.checkedAt,
+	}
+	if result.err != nil {
+		message := result.err.Error()
+		check.Status = dto.HealthStatusDown
+		check.Error = &message
+	}
+
+	return check
+}
@@ -0,0 +1,141 @@
+package service_impl
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"sync"
+	"time"
+
+	"github.com/ivanjabrony/personApi/internal/client"
+	"github.com/ivanjabrony/personApi/internal/mapper"
+	"github.com/ivanjabrony/personApi/internal/model"
+	"github.com/ivanjabrony/personApi/internal/model/dto"
+	"github.com/ivanjabrony/personApi/internal/repository"
+)
+
+type HealthServiceConfig struct {
+	// SchemaVersion is the oldest migration version the binary works with.
+	SchemaVersion uint
+	CheckTimeout  time.Duration
+	CacheTTL      time.Duration
+}
+
+type HealthService struct {
+	healthRepository repository.HealthRepository
+	upstreamMonitor  client.UpstreamMonitor
+	ready            func() bool
+	logger           *slog.Logger
+	config           HealthServiceConfig
+
+	postgres *cachedCheck
+	schema   *cachedCheck
+
+	mu        sync.Mutex
+	upstreams map[string]*cachedCheck
+}
+
+func NewHealthService(
+	healthRepository repository.HealthRepository,
+	upstreamMonitor client.UpstreamMonitor,
+	ready func() bool,
+	logger *slog.Logger,
+	config HealthServiceConfig) *HealthService {
+	service := &HealthService{
+		healthRepository: healthRepository,
+		upstreamMonitor:  upstreamMonitor,
+		ready:            ready,
+		logger:           logger,
+		config:           config,
+		upstreams:        make(map[string]*cachedCheck),
+	}
+	service.postgres = newCachedCheck("postgres", config, healthRepository.Ping)
+	service.schema = newCachedCheck("schema", config, service.checkSchema)
+
+	return service
+}
+
+// CheckReadiness reports down while shutting down, when Postgres does not
+// answer or when the schema is behind the expected version.
+func (service *HealthService) CheckReadiness(ctx context.Context) *dto.HealthDto {
+	health := &dto.HealthDto{
+		Status: dto.HealthStatusUp,
+		Checks: []dto.HealthCheckDto{service.checkServing(), service.postgres.check(ctx), service.schema.check(ctx)},
+	}
+
+	for _, check := range health.Checks {
+		if check.Status != dto.HealthStatusUp {
+			health.Status = dto.HealthStatusDown
+			service.logger.Warn("Readiness check failed", slog.String("Check", check.Name))
+		}
+	}
+
+	return health
+}
+
+// GetHealthDetails adds every enrichment upstream to the readiness checks.
+// An unavailable upstream only degrades the service, enrichment is retried.
+func (service *HealthService) GetHealthDetails(ctx context.Context) *dto.HealthDto {
+	health := service.CheckReadiness(ctx)
+
+	for _, status := range service.upstreamMonitor.UpstreamStatuses() {
+		check := service.upstreamCheck(status.Host).check(ctx)
+
+		upstream := mapper.MapToUpstreamStatusDto(status)
+		check.Upstream = &upstream
+		if check.Status == dto.HealthStatusUp && (status.Breaker == model.BreakerOpen || status.QuotaExhausted) {
+			check.Status = dto.HealthStatusDown
+		}
+
+		if check.Status != dto.HealthStatusUp && health.Status == dto.HealthStatusUp {
+			health.Status = dto.HealthStatusDegraded
+		}
+		health.Checks = append(health.Checks, check)
+	}
+
+	return health
+}
+
+func (service *HealthService) checkServing() dto.HealthCheckDto {
+	check := dto.HealthCheckDto{Name: "serving", Status: dto.HealthStatusUp, CheckedAt: time.Now()}
+	if !service.ready() {
+		message := "shutting down"
+		check.Status = dto.HealthStatusDown
+		check.Error = &message
+	}
+
+	return check
+}
+
+// checkSchema accepts a schema newer than the binary, which is what old
+// instances see during a rolling deploy once the new ones migrated.
+func (service *HealthService) checkSchema(ctx context.Context) error {
+	version, dirty, err := service.healthRepository.SchemaVersion(ctx)
+	if err != nil {
+		return err
+	}
+
+	if dirty {
+		return fmt.Errorf("%w: schema version %d is dirty", model.ErrConflict, version)
+	}
+	if version < service.config.SchemaVersion {
+		return fmt.Errorf("%w: schema is at version %d, expected at least %d", model.ErrConflict, version, service.config.SchemaVersion)
+	}
+
+	return nil
+}
+
+func (service *HealthService) upstreamCheck(host string) *cachedCheck {
+	service.mu.Lock()
+	defer service.mu.Unlock()
+
+	check, ok := service.upstreams[host]
+	if !ok {
+		check = newCachedCheck(host, service.config, func(ctx context.Context) error {
+			return service.upstreamMonitor.Probe(ctx, host)
+		})
+		service.upstreams[host] = check
+	}
+
+	return check
+}
@@ -0,0 +1,59 @@
+package service_impl
+
+import (
+	"context"
+	"errors"
+	"io"
+	"log/slog"
+	"testing"
+
+	"github.com/ivanjabrony/personApi/internal/model"
+)
+
+type stubHealthRepository struct {
+	version uint
+	dirty   bool
+}
+
+func (r stubHealthRepository) Ping(context.Context) error {
+	return nil
+}
+
+func (r stubHealthRepository) SchemaVersion(context.Context) (uint, bool, error) {
+	return r.version, r.dirty, nil
+}
+
+func TestCheckSchema(t *testing.T) {
+	tests := []struct {
+		name    string
+		version uint
+		dirty   bool
+		wantErr bool
+	}{
+		{name: "expected version", version: 11},
+		{name: "newer schema during a rolling deploy", version: 12},
+		{name: "schema behind the binary", version: 10, wantErr: true},
+		{name: "dirty schema", version: 11, dirty: true, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			service := &HealthService{
+				healthRepository: stubHealthRepository{version: tt.version, dirty: tt.dirty},
+				logger:           slog.New(slog.NewTextHandler(io.Discard, nil)),
+				config:           HealthServiceConfig{SchemaVersion: 11},
+			}
+
+			err := service.checkSchema(context.Background())
+			if tt.wantErr {
+				if !errors.Is(err, model.ErrConflict) {
+					t.Fatalf("checkSchema() error = %v, want %v", err, model.ErrConflict)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("checkSchema() error = %v", err)
+			}
+		})
+	}
+}
@@ -2,9 +2,10 @@ package service_impl
 
 import (
 	"context"
+	"fmt"
 	"log/slog"
+	"time"
 
-	"github.com/ivanjabrony/personApi/internal/client"
 	"github.com/ivanjabrony/personApi/internal/mapper"
 	"github.com/ivanjabrony/personApi/internal/model"
 	"github.com/ivanjabrony/personApi/internal/model/dto"
@@ -12,40 +13,19 @@ import (
 )
 
 type PersonService struct {
-	personRepository  repository.PersonRepository
-	logger            *slog.Logger
-	ageclient         client.AgeClient
-	genderClient      client.GenderClient
-	nationalityClient client.NationalityClient
+	personRepository repository.PersonRepository
+	logger           *slog.Logger
 }
 
-func NewPersonService(
-	personRepository repository.PersonRepository,
-	ageclient client.AgeClient,
-	genderClient client.GenderClient,
-	nationalityClient client.NationalityClient,
-	logger *slog.Logger) *PersonService {
-	return &PersonService{personRepository, logger, ageclient, genderClient, nationalityClient}
+func NewPersonService(personRepository repository.PersonRepository, logger *slog.Logger) *PersonService {
+	return &PersonService{personRepository, logger}
 }
 
 func (service *PersonService) CreatePerson(ctx context.Context, newPersonDto *dto.NewPersonDto) (int, error) {
 	person := mapper.MapFromNewPersonDto(newPersonDto)
 	service.logger.Debug("Start of person creation", slog.Any("data", *newPersonDto))
 
-	age, err := service.ageclient.GetAgeByName(ctx, person.Name)
-	if err != nil {
-		service.logger.Error("Couldn't retrieve data from Age client", slog.String("Error", err.Error()))
-	}
-	gender, err := service.genderClient.GetGenderByName(ctx, person.Name)
-	if err != nil {
-		service.logger.Error("Couldn't retrieve data from Gender client", slog.String("Error", err.Error()))
-	}
-	nationality, err := service.nationalityClient.GetNationalityByName(ctx, person.Name)
-	if err != nil {
-		service.logger.Error("Couldn't retrieve data from Nationality client", slog.String("Error", err.Error()))
-	}
-
-	person.Age, person.Gender, person.Nationality = age, gender, nationality
+	person.EnrichmentStatus = model.EnrichmentStatusPending
 	id, err := service.personRepository.Create(ctx, person)
 
 	if err != nil {
@@ -57,9 +37,28 @@ func (service *PersonService) CreatePerson(ctx context.Context, newPersonDto *dt
 	return id, nil
 }
 
-func (service *PersonService) GetPersonById(ctx context.Context, id int) (*dto.PersonDto, error) {
-	service.logger.Debug("Start of reading person", slog.Int("ID", id))
-	person, err := service.personRepository.GetById(ctx, id)
+func (service *PersonService) CreatePersons(ctx context.Context, newPersonDtos []dto.NewPersonDto) ([]int, error) {
+	service.logger.Debug("Start of batch person creation", slog.Int("Count", len(newPersonDtos)))
+
+	persons := make([]model.Person, len(newPersonDtos))
+	for i := range newPersonDtos {
+		persons[i] = *mapper.MapFromNewPersonDto(&newPersonDtos[i])
+		persons[i].EnrichmentStatus = model.EnrichmentStatusPending
+	}
+
+	ids, err := service.personRepository.CreateMany(ctx, persons)
+	if err != nil {
+		service.logger.Error("Repository error while batch creating", slog.String("Error", err.Error()))
+		return nil, err
+	}
+
+	service.logger.Info("Persons successfully created", slog.Int("Count", len(ids)))
+	return ids, nil
+}
+
+func (service *PersonService) GetPersonById(ctx context.Context, id int, includeDeleted bool) (*dto.PersonDto, error) {
+	service.logger.Debug("Start of reading person", slog.Int("ID", id), slog.Bool("IncludeDeleted", includeDeleted))
+	person, err := service.personRepository.GetById(ctx, id, includeDeleted)
 
 	if err != nil {
 		service.logger.Error("Repository error while reading", slog.String("Error", err.Error()))
@@ -70,48 +69,83 @@ func (service *PersonService) GetPersonById(ctx context.Context, id int) (*dto.P
 	return mapper.MapToPersonDto(person), nil
 }
 
-func (service *PersonService) GetAllPersons(ctx context.Context) ([]dto.PersonDto, error) {
-	service.logger.Debug("Start of reading all person")
-	persons, err := service.personRepository.GetAll(ctx)
+func (service *PersonService) GetAllPersons(ctx context.Context, pagination *model.Pagination) (*dto.PaginatedPersonsDto, error) {
+	service.logger.Debug("Start of reading all person", slog.Any("pagination", *pagination))
+	page, err := service.personRepository.GetAll(ctx, pagination)
 
 	if err != nil {
 		service.logger.Error("Repository error while reading all", slog.String("Error", err.Error()))
 		return nil, err
 	}
 
-	service.logger.Info("Persons successfully retrieved")
-	return mapper.MapToManyPersonDto(persons...), nil
+	service.logger.Info("Persons successfully retrieved", slog.Int("Total", page.Total))
+	return mapper.MapToPaginatedPersonsDto(page, pagination), nil
 }
 
-func (service *PersonService) GetPersonsFiltered(ctx context.Context, filter *model.PersonFilter) ([]dto.PersonDto, error) {
-	service.logger.Debug("Start of person filtering", slog.Any("data", *filter))
-	persons, err := service.personRepository.GetFiltered(ctx, filter)
+func (service *PersonService) GetPersonsFiltered(ctx context.Context, filter *model.PersonFilter, pagination *model.Pagination) (*dto.PaginatedPersonsDto, error) {
+	service.logger.Debug("Start of person filtering", slog.Any("data", *filter), slog.Any("pagination", *pagination))
+	page, err := service.personRepository.GetFiltered(ctx, filter, pagination)
 
 	if err != nil {
 		service.logger.Error("Repository error while filtering", slog.String("Error", err.Error()))
 		return nil, err
 	}
 
-	service.logger.Info("Persons successfully filtered")
-	return mapper.MapToManyPersonDto(persons...), nil
+	service.logger.Info("Persons successfully filtered", slog.Int("Total", page.Total))
+	return mapper.MapToPaginatedPersonsDto(page, pagination), nil
 }
 
-func (service *PersonService) UpdatePersonById(ctx context.Context, dto *dto.UpdatePersonDto) error {
-	service.logger.Debug("Start of person updating", slog.Any("data", *dto))
-	err := service.personRepository.Update(ctx, mapper.MapFromUpdatePersonDto(dto))
+func (service *PersonService) UpdatePersonById(ctx context.Context, id int, version int, dto *dto.UpdatePersonDto) (*dto.PersonDto, error) {
+	service.logger.Debug("Start of person updating", slog.Int("ID", id), slog.Int("Version", version), slog.Any("data", *dto))
+	person, err := service.personRepository.Update(ctx, mapper.MapFromUpdatePersonDto(id, version, dto))
 
 	if err != nil {
 		service.logger.Error("Repository error while updating", slog.String("Error", err.Error()))
-		return err
+		return nil, err
 	}
 
-	service.logger.Info("Person successfully updated")
-	return nil
+	service.logger.Info("Person successfully updated", slog.Int("ID", id), slog.Int("Version", person.Version))
+	return mapper.MapToPersonDto(person), nil
+}
+
+func (service *PersonService) PatchPersonById(ctx context.Context, id int, version int, dto *dto.PatchPersonDto) (*dto.PersonDto, error) {
+	service.logger.Debug("Start of person patching", slog.Int("ID", id), slog.Int("Version", version), slog.Any("data", *dto))
+	patch := mapper.MapFromPatchPersonDto(id, version, dto)
+
+	var required []model.FieldError
+	if patch.Name.Set && patch.Name.Value == nil {
+		required = append(required, model.FieldError{Field: "name", Rule: "required"})
+	}
+	if patch.Surname.Set && patch.Surname.Value == nil {
+		required = append(required, model.FieldError{Field: "surname", Rule: "required"})
+	}
+	if len(required) != 0 {
+		return nil, &model.ValidationError{Fields: required}
+	}
+
+	var person *model.Person
+	var err error
+	if patch.IsEmpty() {
+		person, err = service.personRepository.GetById(ctx, id, false)
+		if err == nil && version != model.AnyVersion && person.Version != version {
+			err = fmt.Errorf("person with id %d is at version %d: %w", id, person.Version, model.ErrPreconditionFailed)
+		}
+	} else {
+		person, err = service.personRepository.Patch(ctx, patch)
+	}
+
+	if err != nil {
+		service.logger.Error("Repository error while patching", slog.String("Error", err.Error()))
+		return nil, err
+	}
+
+	service.logger.Info("Person successfully patched", slog.Int("ID", id), slog.Int("Version", person.Version))
+	return mapper.MapToPersonDto(person), nil
 }
 
-func (service *PersonService) DeletePersonById(ctx context.Context, id int) error {
-	service.logger.Debug("Start of person deleting", slog.Int("ID", id))
-	err := service.personRepository.DeleteById(ctx, id)
+func (service *PersonService) DeletePersonById(ctx context.Context, id int, version int) error {
+	service.logger.Debug("Start of person deleting", slog.Int("ID", id), slog.Int("Version", version))
+	err := service.personRepository.DeleteById(ctx, id, version)
 
 	if err != nil {
 		service.logger.Error("Repository error while deleting", slog.String("Error", err.Error()))
@@ -121,3 +155,43 @@ func (service *PersonService) DeletePersonById(ctx context.Context, id int) erro
 	service.logger.Info("Person successfully deleted")
 	return nil
 }
+
+func (service *PersonService) RestorePersonById(ctx context.Context, id int) (*dto.PersonDto, error) {
+	service.logger.Debug("Start of person restoring", slog.Int("ID", id))
+	person, err := service.personRepository.Restore(ctx, id)
+
+	if err != nil {
+		service.logger.Error("Repository error while restoring", slog.String("Error", err.Error()))
+		return nil, err
+	}
+
+	service.logger.Info("Person successfully restored", slog.Int("ID", id))
+	return mapper.MapToPersonDto(person), nil
+}
+
+func (service *PersonService) PurgeDeletedPersons(ctx context.Context, retention time.Duration) (*dto.PurgeResultDto, error) {
+	deletedBefore := time.Now().Add(-retention)
+	service.logger.Debug("Start of deleted persons purging", slog.Time("DeletedBefore", deletedBefore))
+	purged, err := service.personRepository.PurgeDeleted(ctx, deletedBefore)
+
+	if err != nil {
+		service.logger.Error("Repository error while purging", slog.String("Error", err.Error()))
+		return nil, err
+	}
+
+	service.logger.Info("Deleted persons successfully purged", slog.Int64("Purged", purged))
+	return &dto.PurgeResultDto{Purged: purged, DeletedBefore: deletedBefore}, nil
+}
+
+func (service *PersonService) GetPersonHistory(ctx context.Context, id int, pagination *model.Pagination) (*dto.PaginatedPersonAuditDto, error) {
+	service.logger.Debug("Start of reading person history", slog.Int("ID", id), slog.Any("pagination", *pagination))
+	page, err := service.personRepository.GetHistory(ctx, id, pagination)
+
+	if err != nil {
+		service.logger.Error("Repository error while reading history", slog.String("Error", err.Error()))
+		return nil, err
+	}
+
+	service.logger.Info("Person history successfully retrieved", slog.Int("ID", id), slog.Int("Total", page.Total))
+	return mapper.MapToPaginatedPersonAuditDto(page, pagination), nil
+}
@@ -0,0 +1,43 @@
+package service_tracing
+
+import (
+	"context"
+	"time"
+
+	"github.com/ivanjabrony/personApi/internal/model/dto"
+	"github.com/ivanjabrony/personApi/internal/service"
+	"go.opentelemetry.io/otel/attribute"
+)
+
+// TracedEnrichmentService wraps the EnrichmentService calls made on behalf
+// of a request in a span. ProcessEnrichmentJobs is left to the service, it
+// only starts a trace once it has claimed jobs.
+type TracedEnrichmentService struct {
+	next service.EnrichmentService
+}
+
+func NewTracedEnrichmentService(next service.EnrichmentService) *TracedEnrichmentService {
+	return &TracedEnrichmentService{next}
+}
+
+func (s *TracedEnrichmentService) ProcessEnrichmentJobs(ctx context.Context, limit int) (int, error) {
+	return s.next.ProcessEnrichmentJobs(ctx, limit)
+}
+
+func (s *TracedEnrichmentService) GetEnrichmentStatus(ctx context.Context, id int) (*dto.EnrichmentStatusDto, error) {
+	return traced(ctx, "EnrichmentService.GetEnrichmentStatus", func(ctx context.Context) (*dto.EnrichmentStatusDto, error) {
+		return s.next.GetEnrichmentStatus(ctx, id)
+	}, personId(id))
+}
+
+func (s *TracedEnrichmentService) RequestEnrichment(ctx context.Context, id int) (*dto.EnrichmentStatusDto, error) {
+	return traced(ctx, "EnrichmentService.RequestEnrichment", func(ctx context.Context) (*dto.EnrichmentStatusDto, error) {
+		return s.next.RequestEnrichment(ctx, id)
+	}, personId(id))
+}
+
+func (s *TracedEnrichmentService) EnqueueStaleEnrichment(ctx context.Context, afterId int, limit int, staleAfter time.Duration) ([]int, error) {
+	return traced(ctx, "EnrichmentService.EnqueueStaleEnrichment", func(ctx context.Context) ([]int, error) {
+		return s.next.EnqueueStaleEnrichment(ctx, afterId, limit, staleAfter)
+	}, attribute.Int("after.id", afterId), attribute.Int("limit", limit))
+}
@@ -0,0 +1,88 @@
+package service_tracing
+
+import (
+	"context"
+	"time"
+
+	"github.com/ivanjabrony/personApi/internal/model"
+	"github.com/ivanjabrony/personApi/internal/model/dto"
+	"github.com/ivanjabrony/personApi/internal/service"
+	"go.opentelemetry.io/otel/attribute"
+)
+
+// TracedPersonService wraps every PersonService call in a span.
+type TracedPersonService struct {
+	next service.PersonService
+}
+
+func NewTracedPersonService(next service.PersonService) *TracedPersonService {
+	return &TracedPersonService{next}
+}
+
+func (s *TracedPersonService) CreatePerson(ctx context.Context, newPersonDto *dto.NewPersonDto) (int, error) {
+	return traced(ctx, "PersonService.CreatePerson", func(ctx context.Context) (int, error) {
+		return s.next.CreatePerson(ctx, newPersonDto)
+	})
+}
+
+func (s *TracedPersonService) CreatePersons(ctx context.Context, newPersonDtos []dto.NewPersonDto) ([]int, error) {
+	return traced(ctx, "PersonService.CreatePersons", func(ctx context.Context) ([]int, error) {
+		return s.next.CreatePersons(ctx, newPersonDtos)
+	}, attribute.Int("persons.count", len(newPersonDtos)))
+}
+
+func (s *TracedPersonService) GetPersonById(ctx context.Context, id int, includeDeleted bool) (*dto.PersonDto, error) {
+	return traced(ctx, "PersonService.GetPersonById", func(ctx context.Context) (*dto.PersonDto, error) {
+		return s.next.GetPersonById(ctx, id, includeDeleted)
+	}, personId(id))
+}
+
+func (s *TracedPersonService) GetAllPersons(ctx context.Context, pagination *model.Pagination) (*dto.PaginatedPersonsDto, error) {
+	return traced(ctx, "PersonService.GetAllPersons", func(ctx context.Context) (*dto.PaginatedPersonsDto, error) {
+		return s.next.GetAllPersons(ctx, pagination)
+	})
+}
+
+func (s *TracedPersonService) GetPersonsFiltered(ctx context.Context, filter *model.PersonFilter, pagination *model.Pagination) (*dto.PaginatedPersonsDto, error) {
+	return traced(ctx, "PersonService.GetPersonsFiltered", func(ctx context.Context) (*dto.PaginatedPersonsDto, error) {
+		return s.next.GetPersonsFiltered(ctx, filter, pagination)
+	})
+}
+
+func (s *TracedPersonService) UpdatePersonById(ctx context.Context, id int, version int, updatePersonDto *dto.UpdatePersonDto) (*dto.PersonDto, error) {
+	return traced(ctx, "PersonService.UpdatePersonById", func(ctx context.Context) (*dto.PersonDto, error) {
+		return s.next.UpdatePersonById(ctx, id, version, updatePersonDto)
+	}, personId(id))
+}
+
+func (s *TracedPersonService) PatchPersonById(ctx context.Context, id int, version int, patchPersonDto *dto.PatchPersonDto) (*dto.PersonDto, error) {
+	return traced(ctx, "PersonService.PatchPersonById", func(ctx context.Context) (*dto.PersonDto, error) {
+		return s.next.PatchPersonById(ctx, id, version, patchPersonDto)
+	}, personId(id))
+}
+
+func (s *TracedPersonService) DeletePersonById(ctx context.Context, id int, version int) error {
+	_, err := traced(ctx, "PersonService.DeletePersonById", func(ctx context.Context) (struct{}, error) {
+		return struct{}{}, s.next.DeletePersonById(ctx, id, version)
+	}, personId(id))
+
+	return err
+}
+
+func (s *TracedPersonService) RestorePersonById(ctx context.Context, id int) (*dto.PersonDto, error) {
+	return traced(ctx, "PersonService.RestorePersonById", func(ctx context.Context) (*dto.PersonDto, error) {
+		return s.next.RestorePersonById(ctx, id)
+	}, personId(id))
+}
+
+func (s *TracedPersonService) PurgeDeletedPersons(ctx context.Context, retention time.Duration) (*dto.PurgeResultDto, error) {
+	return traced(ctx, "PersonService.PurgeDeletedPersons", func(ctx context.Context) (*dto.PurgeResultDto, error) {
+		return s.next.PurgeDeletedPersons(ctx, retention)
+	})
+}
+
+func (s *TracedPersonService) GetPersonHistory(ctx context.Context, id int, pagination *model.Pagination) (*dto.PaginatedPersonAuditDto, error) {
+	return traced(ctx, "PersonService.GetPersonHistory", func(ctx context.Context) (*dto.PaginatedPersonAuditDto, error) {
+		return s.next.GetPersonHistory(ctx, id, pagination)
+	}, personId(id))
+}
@@ -0,0 +1,25 @@
+package service_tracing
+
+import (
+	"context"
+
+	"github.com/ivanjabrony/personApi/internal/tracing"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+)
+
+var tracer = otel.Tracer("github.com/ivanjabrony/personApi/internal/service")
+
+func traced[T any](ctx context.Context, name string, call func(context.Context) (T, error), attributes ...attribute.KeyValue) (T, error) {
+	ctx, span := tracer.Start(ctx, name)
+	span.SetAttributes(attributes...)
+
+	value, err := call(ctx)
+	tracing.End(span, err)
+
+	return value, err
+}
+
+func personId(id int) attribute.KeyValue {
+	return attribute.Int("person.id", id)
+}
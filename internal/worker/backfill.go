@@ -0,0 +1,64 @@
+package worker
+
+import (
+	"context"
+	"log/slog"
+	"time"
+
+	"github.com/ivanjabrony/personApi/internal/service"
+)
+
+type BackfillConfig struct {
+	BatchSize  int
+	Interval   time.Duration
+	StaleAfter time.Duration
+}
+
+// Backfiller walks the persons table once, queueing persons whose enrichment
+// is missing or older than StaleAfter. Per Interval it queues one batch and
+// works off at most one batch of the queue, which bounds the rate of upstream
+// lookups; what is left is drained by the regular worker.
+type Backfiller struct {
+	enrichmentService service.EnrichmentService
+	logger            *slog.Logger
+	config            BackfillConfig
+}
+
+func NewBackfiller(enrichmentService service.EnrichmentService, logger *slog.Logger, config BackfillConfig) *Backfiller {
+	return &Backfiller{enrichmentService, logger, config}
+}
+
+// Run returns the amount of queued persons once the table is walked through.
+func (b *Backfiller) Run(ctx context.Context) (int, error) {
+	batchSize := max(b.config.BatchSize, 1)
+	ticker := time.NewTicker(max(b.config.Interval, time.Millisecond))
+	defer ticker.Stop()
+
+	afterId, queued := 0, 0
+	for {
+		ids, err := b.enrichmentService.EnqueueStaleEnrichment(ctx, afterId, batchSize, b.config.StaleAfter)
+		if err != nil {
+			return queued, err
+		}
+		if len(ids) == 0 {
+			b.logger.Info("Backfill finished", slog.Int("Queued", queued))
+			return queued, nil
+		}
+
+		afterId = ids[len(ids)-1]
+		queued += len(ids)
+
+		_, err = b.enrichmentService.ProcessEnrichmentJobs(ctx, batchSize)
+		if err != nil {
+			return queued, err
+		}
+
+		b.logger.Info("Backfill batch processed", slog.Int("LastID", afterId), slog.Int("Queued", queued))
+
+		select {
+		case <-ctx.Done():
+			return queued, ctx.Err()
+		case <-ticker.C:
+		}
+	}
+}
@@ -0,0 +1,66 @@
+package worker
+
+import (
+	"context"
+	"log/slog"
+	"sync"
+	"time"
+
+	"github.com/ivanjabrony/personApi/internal/service"
+)
+
+type Config struct {
+	Workers      int
+	BatchSize    int
+	PollInterval time.Duration
+}
+
+// EnrichmentWorker drains the enrichment queue with a pool of pollers. A poller
+// keeps claiming batches while there is work and sleeps for PollInterval once
+// the queue is empty or the database fails.
+type EnrichmentWorker struct {
+	enrichmentService service.EnrichmentService
+	logger            *slog.Logger
+	config            Config
+}
+
+func NewEnrichmentWorker(enrichmentService service.EnrichmentService, logger *slog.Logger, config Config) *EnrichmentWorker {
+	return &EnrichmentWorker{enrichmentService, logger, config}
+}
+
+// Run blocks until ctx is cancelled and every poller has returned. A batch
+// already claimed is finished first, so its jobs are not left leased.
+func (w *EnrichmentWorker) Run(ctx context.Context) {
+	var wg sync.WaitGroup
+
+	w.logger.Info("Enrichment worker started", slog.Int("Workers", w.config.Workers))
+	for range max(w.config.Workers, 1) {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			w.poll(ctx)
+		}()
+	}
+	wg.Wait()
+	w.logger.Info("Enrichment worker stopped")
+}
+
+func (w *EnrichmentWorker) poll(ctx context.Context) {
+	timer := time.NewTimer(0)
+	defer timer.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-timer.C:
+		}
+
+		claimed, err := w.enrichmentService.ProcessEnrichmentJobs(context.WithoutCancel(ctx), max(w.config.BatchSize, 1))
+		if err != nil || claimed == 0 {
+			timer.Reset(w.config.PollInterval)
+		} else {
+			timer.Reset(0)
+		}
+	}
+}
@@ -0,0 +1,3 @@
+ALTER TABLE persons ADD COLUMN deleted_at TIMESTAMPTZ NULL;
+
+CREATE INDEX persons_deleted_at_idx ON persons(deleted_at) WHERE deleted_at IS NOT NULL;
\ No newline at end of file
@@ -0,0 +1,22 @@
+CREATE TABLE person_audit (
+  id BIGSERIAL PRIMARY KEY,
+  person_id INT NOT NULL,
+  action TEXT NOT NULL,
+  before JSONB NULL,
+  after JSONB NULL,
+  actor TEXT NULL,
+  request_id TEXT NULL,
+  created_at TIMESTAMPTZ NOT NULL DEFAULT now()
+);
+
+CREATE INDEX person_audit_person_id_idx ON person_audit(person_id, id);
+
+CREATE FUNCTION person_audit_append_only() RETURNS trigger AS $$
+BEGIN
+  RAISE EXCEPTION 'person_audit is append-only';
+END;
+$$ LANGUAGE plpgsql;
+
+CREATE TRIGGER person_audit_append_only
+  BEFORE UPDATE OR DELETE ON person_audit
+  FOR EACH ROW EXECUTE FUNCTION person_audit_append_only();
\ No newline at end of file
@@ -0,0 +1,9 @@
+CREATE TABLE enrichment_cache (
+  kind TEXT NOT NULL,
+  name TEXT NOT NULL,
+  value JSONB NOT NULL,
+  expires_at TIMESTAMPTZ NOT NULL,
+  PRIMARY KEY (kind, name)
+);
+
+CREATE INDEX enrichment_cache_expires_at_idx ON enrichment_cache(expires_at);
\ No newline at end of file
@@ -0,0 +1,14 @@
+ALTER TABLE persons ADD COLUMN enrichment_status TEXT NOT NULL DEFAULT 'complete';
+
+CREATE TABLE enrichment_jobs (
+  id BIGSERIAL PRIMARY KEY,
+  person_id INT NOT NULL UNIQUE REFERENCES persons(id) ON DELETE CASCADE,
+  state TEXT NOT NULL DEFAULT 'pending',
+  attempts INT NOT NULL DEFAULT 0,
+  last_error TEXT NULL,
+  run_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+  created_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+  updated_at TIMESTAMPTZ NOT NULL DEFAULT now()
+);
+
+CREATE INDEX enrichment_jobs_runnable_idx ON enrichment_jobs(run_at) WHERE state IN ('pending', 'running');
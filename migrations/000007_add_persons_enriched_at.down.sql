@@ -0,0 +1,3 @@
+ALTER TABLE enrichment_jobs DROP COLUMN IF EXISTS overwrite;
+
+ALTER TABLE persons DROP COLUMN IF EXISTS enriched_at;
\ No newline at end of file
@@ -0,0 +1,3 @@
+ALTER TABLE persons ADD COLUMN enriched_at TIMESTAMPTZ NULL;
+
+ALTER TABLE enrichment_jobs ADD COLUMN overwrite BOOLEAN NOT NULL DEFAULT false;
@@ -0,0 +1 @@
+DROP TABLE IF EXISTS "person_enrichment_candidates";
\ No newline at end of file
@@ -0,0 +1,9 @@
+CREATE TABLE person_enrichment_candidates (
+  person_id INT NOT NULL REFERENCES persons(id) ON DELETE CASCADE,
+  kind TEXT NOT NULL,
+  rank INT NOT NULL,
+  value TEXT NOT NULL,
+  probability DOUBLE PRECISION NULL,
+  sample_count INT NULL,
+  PRIMARY KEY (person_id, kind, rank)
+);
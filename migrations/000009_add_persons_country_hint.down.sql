@@ -0,0 +1,3 @@
+ALTER TABLE person_enrichment_candidates DROP COLUMN IF EXISTS country_id;
+
+ALTER TABLE persons DROP COLUMN IF EXISTS country_hint;
\ No newline at end of file
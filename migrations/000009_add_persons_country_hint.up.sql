@@ -0,0 +1,3 @@
+ALTER TABLE persons ADD COLUMN country_hint TEXT NULL;
+
+ALTER TABLE person_enrichment_candidates ADD COLUMN country_id TEXT NULL;
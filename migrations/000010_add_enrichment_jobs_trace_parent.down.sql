@@ -0,0 +1 @@
+ALTER TABLE enrichment_jobs DROP COLUMN IF EXISTS trace_parent;
@@ -0,0 +1 @@
+ALTER TABLE enrichment_jobs ADD COLUMN trace_parent TEXT NULL;
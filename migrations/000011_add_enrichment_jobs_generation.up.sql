@@ -0,0 +1 @@
+ALTER TABLE enrichment_jobs ADD COLUMN generation BIGINT NOT NULL DEFAULT 0;
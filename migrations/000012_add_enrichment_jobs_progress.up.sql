@@ -0,0 +1 @@
+ALTER TABLE enrichment_jobs ADD COLUMN progress JSONB NULL;